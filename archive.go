@@ -0,0 +1,181 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+)
+
+// ArchiveFile is where completed tasks are moved once their retention
+// period has elapsed.
+const ArchiveFile = "tasks.archive.json"
+
+// loadArchive reads the archive file, returning an empty list if it does
+// not exist yet.
+func loadArchive() (*TaskList, error) {
+	al := &TaskList{Tasks: []Task{}}
+
+	if _, err := os.Stat(ArchiveFile); os.IsNotExist(err) {
+		return al, nil
+	}
+
+	data, err := os.ReadFile(ArchiveFile)
+	if err != nil {
+		return nil, fmt.Errorf("error reading archive file: %v", err)
+	}
+	if len(data) == 0 {
+		return al, nil
+	}
+
+	if err := json.Unmarshal(data, al); err != nil {
+		return nil, fmt.Errorf("error parsing archive file: %v", err)
+	}
+	return al, nil
+}
+
+// saveArchive writes the archive file.
+func saveArchive(al *TaskList) error {
+	data, err := json.MarshalIndent(al, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error marshaling archive: %v", err)
+	}
+	if err := os.WriteFile(ArchiveFile, data, 0644); err != nil {
+		return fmt.Errorf("error writing archive file: %v", err)
+	}
+	return nil
+}
+
+// isRetentionExpired reports whether a completed task's retention window
+// has elapsed as of now.
+func isRetentionExpired(t Task, now time.Time) bool {
+	if t.Status != StatusDone || t.CompletedAt == nil || t.Retention <= 0 {
+		return false
+	}
+	return now.After(t.CompletedAt.Add(t.Retention))
+}
+
+// archiveTasks moves every completed task whose retention period has
+// elapsed out of the active store and into tasks.archive.json.
+func archiveTasks() error {
+	archive, err := loadArchive()
+	if err != nil {
+		return err
+	}
+
+	tasks, err := store.List(Filter{})
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	moved := 0
+	for _, task := range tasks {
+		if !isRetentionExpired(task, now) {
+			continue
+		}
+		if err := store.Delete(task.ID); err != nil {
+			return err
+		}
+		archive.Tasks = append(archive.Tasks, task)
+		moved++
+	}
+
+	if moved == 0 {
+		fmt.Println("No tasks are eligible for archiving.")
+		return nil
+	}
+
+	if err := saveArchive(archive); err != nil {
+		return err
+	}
+
+	fmt.Printf("Archived %d task(s).\n", moved)
+	return nil
+}
+
+// restoreTask moves a task back from the archive into the active task
+// list.
+func restoreTask(id int) error {
+	archive, err := loadArchive()
+	if err != nil {
+		return err
+	}
+
+	for i, task := range archive.Tasks {
+		if task.ID == id {
+			if err := store.Restore(task); err != nil {
+				return err
+			}
+
+			archive.Tasks = append(archive.Tasks[:i], archive.Tasks[i+1:]...)
+			if err := saveArchive(archive); err != nil {
+				return err
+			}
+			fmt.Printf("Task %d restored from archive\n", id)
+			return nil
+		}
+	}
+
+	return fmt.Errorf("task with ID %d not found in archive", id)
+}
+
+// purgeArchive permanently deletes archived tasks completed before the
+// given cutoff.
+func purgeArchive(before time.Time) error {
+	archive, err := loadArchive()
+	if err != nil {
+		return err
+	}
+
+	kept := archive.Tasks[:0]
+	purged := 0
+	for _, task := range archive.Tasks {
+		if task.CompletedAt != nil && task.CompletedAt.Before(before) {
+			purged++
+			continue
+		}
+		kept = append(kept, task)
+	}
+	archive.Tasks = kept
+
+	if purged == 0 {
+		fmt.Println("No archived tasks matched the given cutoff.")
+		return nil
+	}
+
+	if err := saveArchive(archive); err != nil {
+		return err
+	}
+
+	fmt.Printf("Purged %d archived task(s).\n", purged)
+	return nil
+}
+
+// listArchivedTasks prints every task currently in the archive.
+func listArchivedTasks() error {
+	archive, err := loadArchive()
+	if err != nil {
+		return err
+	}
+
+	if len(archive.Tasks) == 0 {
+		fmt.Println("No archived tasks found.")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tDescription\tCompleted")
+	fmt.Fprintln(w, "---\t-----------\t---------")
+	for _, task := range archive.Tasks {
+		completed := "-"
+		if task.CompletedAt != nil {
+			completed = task.CompletedAt.Format("2006-01-02 15:04")
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\n", task.ID, task.Description, completed)
+	}
+	w.Flush()
+
+	return nil
+}
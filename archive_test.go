@@ -0,0 +1,127 @@
+package main
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+// withTempStore chdirs into a fresh temp dir (so ArchiveFile/TasksFile land
+// there) and points the package-level store at a json backend in it,
+// restoring both on test cleanup.
+func withTempStore(t *testing.T) {
+	t.Helper()
+
+	dir := t.TempDir()
+	origWD, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Getwd: %v", err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatalf("Chdir: %v", err)
+	}
+
+	origStore := store
+	s, err := newJSONStorage(TasksFile)
+	if err != nil {
+		t.Fatalf("newJSONStorage: %v", err)
+	}
+	store = s
+
+	t.Cleanup(func() {
+		store = origStore
+		os.Chdir(origWD)
+	})
+}
+
+func TestIsRetentionExpired(t *testing.T) {
+	now := time.Now()
+	done := now.Add(-2 * time.Hour)
+
+	cases := []struct {
+		name string
+		task Task
+		want bool
+	}{
+		{"not done", Task{Status: StatusTodo}, false},
+		{"no retention set", Task{Status: StatusDone, CompletedAt: &done}, false},
+		{"retention not yet elapsed", Task{Status: StatusDone, CompletedAt: &done, Retention: 24 * time.Hour}, false},
+		{"retention elapsed", Task{Status: StatusDone, CompletedAt: &done, Retention: time.Hour}, true},
+	}
+
+	for _, c := range cases {
+		if got := isRetentionExpired(c.task, now); got != c.want {
+			t.Errorf("%s: isRetentionExpired() = %v, want %v", c.name, got, c.want)
+		}
+	}
+}
+
+func TestRestoreTaskMovesFromArchiveToStore(t *testing.T) {
+	withTempStore(t)
+
+	task := Task{ID: 5, Description: "finished work", Status: StatusDone, CreatedAt: time.Now(), UpdatedAt: time.Now()}
+	if err := saveArchive(&TaskList{Tasks: []Task{task}}); err != nil {
+		t.Fatalf("saveArchive: %v", err)
+	}
+
+	if err := restoreTask(5); err != nil {
+		t.Fatalf("restoreTask: %v", err)
+	}
+
+	found, err := store.Find(5)
+	if err != nil {
+		t.Fatalf("Find: %v", err)
+	}
+	if found == nil {
+		t.Fatal("restoreTask did not re-insert the task into the store")
+	}
+
+	archive, err := loadArchive()
+	if err != nil {
+		t.Fatalf("loadArchive: %v", err)
+	}
+	if len(archive.Tasks) != 0 {
+		t.Errorf("archive still has %d task(s) after restore, want 0", len(archive.Tasks))
+	}
+}
+
+// TestRestoreTaskKeepsArchiveOnStoreFailure is the review regression: if
+// store.Restore fails (here, because a task with the same ID already
+// exists), the task must not be dropped from the archive.
+func TestRestoreTaskKeepsArchiveOnStoreFailure(t *testing.T) {
+	withTempStore(t)
+
+	now := time.Now()
+	if err := store.Restore(Task{ID: 5, Description: "already active", Status: StatusTodo, CreatedAt: now, UpdatedAt: now}); err != nil {
+		t.Fatalf("seeding store: %v", err)
+	}
+
+	archived := Task{ID: 5, Description: "archived copy", Status: StatusDone, CreatedAt: now, UpdatedAt: now}
+	if err := saveArchive(&TaskList{Tasks: []Task{archived}}); err != nil {
+		t.Fatalf("saveArchive: %v", err)
+	}
+
+	if err := restoreTask(5); err == nil {
+		t.Fatal("expected restoreTask to fail on an ID collision, got nil")
+	}
+
+	archive, err := loadArchive()
+	if err != nil {
+		t.Fatalf("loadArchive: %v", err)
+	}
+	if len(archive.Tasks) != 1 {
+		t.Errorf("archive has %d task(s) after a failed restore, want the task to remain (1)", len(archive.Tasks))
+	}
+}
+
+func TestRestoreTaskNotFound(t *testing.T) {
+	withTempStore(t)
+
+	if err := saveArchive(&TaskList{Tasks: []Task{}}); err != nil {
+		t.Fatalf("saveArchive: %v", err)
+	}
+
+	if err := restoreTask(999); err == nil {
+		t.Error("expected an error restoring a task not in the archive, got nil")
+	}
+}
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cronSchedule is a minimal five-field cron expression (minute hour
+// day-of-month month day-of-week), supporting `*`, comma lists, `a-b`
+// ranges and `*/N` steps - enough for `recurrence.go`'s `cron:` schedules.
+// It deliberately doesn't support named months/days or the `L`/`W`/`#`
+// extensions some cron dialects add.
+//
+// Following standard cron semantics, day-of-month and day-of-week combine
+// with OR, not AND, when both are restricted (domWildcard/dowWildcard
+// track whether each field was literally "*"): a date matches if it
+// satisfies either field. If only one is restricted, the other is
+// ignored; if neither is restricted, every day matches.
+type cronSchedule struct {
+	minutes, hours, doms, months, dows []int
+	domWildcard, dowWildcard           bool
+}
+
+// parseCronExpr parses a five-field cron expression into a cronSchedule.
+func parseCronExpr(expr string) (*cronSchedule, error) {
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("cron expression %q must have 5 fields, got %d", expr, len(fields))
+	}
+
+	minutes, err := parseCronField(fields[0], 0, 59)
+	if err != nil {
+		return nil, fmt.Errorf("minute field: %v", err)
+	}
+	hours, err := parseCronField(fields[1], 0, 23)
+	if err != nil {
+		return nil, fmt.Errorf("hour field: %v", err)
+	}
+	doms, err := parseCronField(fields[2], 1, 31)
+	if err != nil {
+		return nil, fmt.Errorf("day-of-month field: %v", err)
+	}
+	months, err := parseCronField(fields[3], 1, 12)
+	if err != nil {
+		return nil, fmt.Errorf("month field: %v", err)
+	}
+	dows, err := parseCronField(fields[4], 0, 6)
+	if err != nil {
+		return nil, fmt.Errorf("day-of-week field: %v", err)
+	}
+
+	return &cronSchedule{
+		minutes: minutes, hours: hours, doms: doms, months: months, dows: dows,
+		domWildcard: fields[2] == "*", dowWildcard: fields[4] == "*",
+	}, nil
+}
+
+// parseCronField expands one cron field into the sorted list of values it
+// matches within [min, max]. "*" expands to the whole range.
+func parseCronField(field string, min, max int) ([]int, error) {
+	seen := map[int]bool{}
+	for _, part := range strings.Split(field, ",") {
+		if part == "*" {
+			for v := min; v <= max; v++ {
+				seen[v] = true
+			}
+			continue
+		}
+
+		step := 1
+		rangePart := part
+		if before, after, ok := strings.Cut(part, "/"); ok {
+			rangePart = before
+			n, err := strconv.Atoi(after)
+			if err != nil || n <= 0 {
+				return nil, fmt.Errorf("invalid step in %q", part)
+			}
+			step = n
+		}
+
+		lo, hi := min, max
+		if rangePart != "*" {
+			if a, b, ok := strings.Cut(rangePart, "-"); ok {
+				var err error
+				lo, err = strconv.Atoi(a)
+				if err != nil {
+					return nil, fmt.Errorf("invalid range start in %q", part)
+				}
+				hi, err = strconv.Atoi(b)
+				if err != nil {
+					return nil, fmt.Errorf("invalid range end in %q", part)
+				}
+			} else {
+				v, err := strconv.Atoi(rangePart)
+				if err != nil {
+					return nil, fmt.Errorf("invalid value %q", part)
+				}
+				lo, hi = v, v
+			}
+		}
+		if lo < min || hi > max || lo > hi {
+			return nil, fmt.Errorf("%q is out of range [%d, %d]", part, min, max)
+		}
+		for v := lo; v <= hi; v += step {
+			seen[v] = true
+		}
+	}
+
+	values := make([]int, 0, len(seen))
+	for v := range seen {
+		values = append(values, v)
+	}
+	sortInts(values)
+	return values, nil
+}
+
+// sortInts sorts ascending without pulling in "sort" for a handful of ints.
+func sortInts(vs []int) {
+	for i := 1; i < len(vs); i++ {
+		for j := i; j > 0 && vs[j-1] > vs[j]; j-- {
+			vs[j-1], vs[j] = vs[j], vs[j-1]
+		}
+	}
+}
+
+// maxCronSearch bounds how far into the future Next will search before
+// giving up - four years of minutes, comfortably past any leap-year cycle.
+const maxCronSearch = 4 * 366 * 24 * 60
+
+// Next returns the first minute strictly after t that satisfies every
+// field, computed in t's own location so DST transitions fall out of
+// time.Date's normalization instead of needing special-casing.
+func (c *cronSchedule) Next(t time.Time) (time.Time, error) {
+	loc := t.Location()
+	candidate := time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), 0, 0, loc).Add(time.Minute)
+
+	for i := 0; i < maxCronSearch; i++ {
+		if containsInt(c.months, int(candidate.Month())) &&
+			c.dayMatches(candidate) &&
+			containsInt(c.hours, candidate.Hour()) &&
+			containsInt(c.minutes, candidate.Minute()) {
+			return candidate, nil
+		}
+		candidate = candidate.Add(time.Minute)
+	}
+	return time.Time{}, fmt.Errorf("no matching time found within %d minutes", maxCronSearch)
+}
+
+// dayMatches applies cron's day-of-month/day-of-week OR rule: when both
+// fields are restricted, either matching is enough; when only one is
+// restricted, it alone decides; when neither is, every day matches.
+func (c *cronSchedule) dayMatches(t time.Time) bool {
+	domMatch := containsInt(c.doms, t.Day())
+	dowMatch := containsInt(c.dows, int(t.Weekday()))
+
+	switch {
+	case !c.domWildcard && !c.dowWildcard:
+		return domMatch || dowMatch
+	case !c.domWildcard:
+		return domMatch
+	case !c.dowWildcard:
+		return dowMatch
+	default:
+		return true
+	}
+}
+
+func containsInt(vs []int, v int) bool {
+	for _, x := range vs {
+		if x == v {
+			return true
+		}
+	}
+	return false
+}
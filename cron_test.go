@@ -0,0 +1,109 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseCronField(t *testing.T) {
+	cases := []struct {
+		field string
+		min   int
+		max   int
+		want  []int
+	}{
+		{"*", 0, 4, []int{0, 1, 2, 3, 4}},
+		{"1,3", 0, 5, []int{1, 3}},
+		{"1-3", 0, 5, []int{1, 2, 3}},
+		{"*/15", 0, 59, []int{0, 15, 30, 45}},
+		{"1-10/3", 0, 59, []int{1, 4, 7, 10}},
+	}
+
+	for _, c := range cases {
+		got, err := parseCronField(c.field, c.min, c.max)
+		if err != nil {
+			t.Errorf("parseCronField(%q): unexpected error: %v", c.field, err)
+			continue
+		}
+		if len(got) != len(c.want) {
+			t.Errorf("parseCronField(%q) = %v, want %v", c.field, got, c.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != c.want[i] {
+				t.Errorf("parseCronField(%q) = %v, want %v", c.field, got, c.want)
+				break
+			}
+		}
+	}
+}
+
+func TestParseCronFieldOutOfRange(t *testing.T) {
+	if _, err := parseCronField("60", 0, 59); err == nil {
+		t.Error("expected an error for an out-of-range value, got nil")
+	}
+}
+
+func TestParseCronExprWrongFieldCount(t *testing.T) {
+	if _, err := parseCronExpr("0 9 * *"); err == nil {
+		t.Error("expected an error for a 4-field expression, got nil")
+	}
+}
+
+// TestCronNextDayOrSemantics is the regression case from review: when both
+// day-of-month and day-of-week are restricted, standard cron ORs them
+// instead of ANDing, so "0 9 15 * 1-5" from a weekday gives the very next
+// weekday 9am rather than waiting for the 15th to also be a weekday.
+func TestCronNextDayOrSemantics(t *testing.T) {
+	sched, err := parseCronExpr("0 9 15 * 1-5")
+	if err != nil {
+		t.Fatalf("parseCronExpr: %v", err)
+	}
+
+	from := time.Date(2026, time.July, 29, 10, 0, 0, 0, time.UTC) // Wednesday
+	got, err := sched.Next(from)
+	if err != nil {
+		t.Fatalf("Next: %v", err)
+	}
+
+	want := time.Date(2026, time.July, 30, 9, 0, 0, 0, time.UTC) // Thursday
+	if !got.Equal(want) {
+		t.Errorf("Next(%v) = %v, want %v", from, got, want)
+	}
+}
+
+func TestCronNextDayOfMonthOnly(t *testing.T) {
+	sched, err := parseCronExpr("0 9 15 * *")
+	if err != nil {
+		t.Fatalf("parseCronExpr: %v", err)
+	}
+
+	from := time.Date(2026, time.July, 29, 10, 0, 0, 0, time.UTC)
+	got, err := sched.Next(from)
+	if err != nil {
+		t.Fatalf("Next: %v", err)
+	}
+
+	want := time.Date(2026, time.August, 15, 9, 0, 0, 0, time.UTC)
+	if !got.Equal(want) {
+		t.Errorf("Next(%v) = %v, want %v", from, got, want)
+	}
+}
+
+func TestCronNextBothWildcard(t *testing.T) {
+	sched, err := parseCronExpr("30 8 * * *")
+	if err != nil {
+		t.Fatalf("parseCronExpr: %v", err)
+	}
+
+	from := time.Date(2026, time.July, 29, 10, 0, 0, 0, time.UTC)
+	got, err := sched.Next(from)
+	if err != nil {
+		t.Fatalf("Next: %v", err)
+	}
+
+	want := time.Date(2026, time.July, 30, 8, 30, 0, 0, time.UTC)
+	if !got.Equal(want) {
+		t.Errorf("Next(%v) = %v, want %v", from, got, want)
+	}
+}
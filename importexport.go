@@ -0,0 +1,277 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Aryan-Shrivastva/task-tracker/internal/runner"
+)
+
+// csvColumns is both the header row written by `export --format csv` and
+// the column order expected when reading one back in.
+var csvColumns = []string{
+	"id", "description", "status", "priority", "due_date", "tags",
+	"dependencies", "completed_at", "retention", "local_id", "remote_id", "created_at", "updated_at", "recurrence",
+}
+
+// exportTasks writes every task in store to w in the given format, showing
+// a progress bar on stderr (unless silent or noProgress) via internal/runner.
+// JSON export writes one task object per line (JSON Lines) rather than a
+// single array, so a run interrupted mid-export still leaves w holding
+// complete, individually parseable records.
+func exportTasks(w io.Writer, format string, silent, noProgress bool) error {
+	tasks, err := store.List(Filter{})
+	if err != nil {
+		return err
+	}
+
+	bw := bufio.NewWriter(w)
+	var csvw *csv.Writer
+
+	switch format {
+	case "csv":
+		csvw = csv.NewWriter(bw)
+		if err := csvw.Write(csvColumns); err != nil {
+			return fmt.Errorf("error writing CSV header: %v", err)
+		}
+	case "json", "md":
+		// no header
+	default:
+		return fmt.Errorf("unknown export format %q, expected csv, json or md", format)
+	}
+
+	writeOne := func(ctx context.Context, i int) error {
+		t := tasks[i]
+		switch format {
+		case "csv":
+			if err := csvw.Write(taskToCSVRow(t)); err != nil {
+				return err
+			}
+			csvw.Flush()
+			return csvw.Error()
+		case "json":
+			data, err := json.Marshal(t)
+			if err != nil {
+				return err
+			}
+			_, err = fmt.Fprintln(bw, string(data))
+			return err
+		default: // md
+			_, err := fmt.Fprintln(bw, taskToMarkdown(t))
+			return err
+		}
+	}
+
+	opts := runner.Options{Silent: silent, NoProgress: noProgress}
+	if err := runner.Run(len(tasks), opts, writeOne, bw.Flush); err != nil {
+		return err
+	}
+	if !silent {
+		fmt.Fprintf(os.Stderr, "Exported %d task(s) as %s\n", len(tasks), format)
+	}
+	return nil
+}
+
+// taskToCSVRow renders a task as a row matching csvColumns.
+func taskToCSVRow(t Task) []string {
+	due, completed := "", ""
+	if t.DueDate != nil {
+		due = t.DueDate.Format(time.RFC3339)
+	}
+	if t.CompletedAt != nil {
+		completed = t.CompletedAt.Format(time.RFC3339)
+	}
+	return []string{
+		strconv.Itoa(t.ID), t.Description, t.Status, t.Priority, due,
+		strings.Join(t.Tags, ","), joinIDs(t.Dependencies), completed,
+		t.Retention.String(), t.LocalID, t.RemoteID,
+		t.CreatedAt.Format(time.RFC3339), t.UpdatedAt.Format(time.RFC3339), t.Recurrence,
+	}
+}
+
+// taskToMarkdown renders a task as a single markdown checklist line.
+func taskToMarkdown(t Task) string {
+	box := " "
+	if t.Status == StatusDone {
+		box = "x"
+	}
+	line := fmt.Sprintf("- [%s] #%d %s", box, t.ID, t.Description)
+
+	var extra []string
+	if t.Priority != "" {
+		extra = append(extra, t.Priority)
+	}
+	if t.DueDate != nil {
+		extra = append(extra, "due "+t.DueDate.Format(dueDateLayout))
+	}
+	if len(t.Tags) > 0 {
+		extra = append(extra, strings.Join(t.Tags, ","))
+	}
+	if len(extra) > 0 {
+		line += " (" + strings.Join(extra, ", ") + ")"
+	}
+	return line
+}
+
+// importTasks reads tasks from path (format inferred from its .csv/.json
+// extension) and restores them into store at their original IDs, showing a
+// progress bar via internal/runner. A task whose ID already exists is
+// skipped rather than treated as fatal, so re-running an import is safe.
+func importTasks(path string, silent, noProgress bool) error {
+	var tasks []Task
+	var err error
+	switch {
+	case strings.HasSuffix(path, ".csv"):
+		tasks, err = readCSVTasks(path)
+	case strings.HasSuffix(path, ".json"):
+		tasks, err = readJSONTasks(path)
+	default:
+		return fmt.Errorf("unrecognized import file extension for %q, expected .csv or .json", path)
+	}
+	if err != nil {
+		return err
+	}
+
+	skipped := 0
+	importOne := func(ctx context.Context, i int) error {
+		t := tasks[i]
+		existing, err := store.Find(t.ID)
+		if err != nil {
+			return err
+		}
+		if existing != nil {
+			skipped++
+			return nil
+		}
+		return store.Restore(t)
+	}
+
+	opts := runner.Options{Silent: silent, NoProgress: noProgress}
+	noopFlush := func() error { return nil }
+	if err := runner.Run(len(tasks), opts, importOne, noopFlush); err != nil {
+		return err
+	}
+	if !silent {
+		fmt.Fprintf(os.Stderr, "Imported %d task(s), skipped %d already present\n", len(tasks)-skipped, skipped)
+	}
+	return nil
+}
+
+// readCSVTasks parses a CSV file written by `export --format csv`.
+func readCSVTasks(path string) ([]Task, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("error opening import file: %v", err)
+	}
+	defer f.Close()
+
+	rows, err := csv.NewReader(f).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("error parsing CSV: %v", err)
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+
+	tasks := make([]Task, 0, len(rows)-1)
+	for _, row := range rows[1:] { // skip header
+		t, err := taskFromCSVRow(row)
+		if err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, t)
+	}
+	return tasks, nil
+}
+
+// taskFromCSVRow parses a row matching csvColumns back into a Task.
+func taskFromCSVRow(row []string) (Task, error) {
+	if len(row) != len(csvColumns) {
+		return Task{}, fmt.Errorf("expected %d CSV columns, got %d", len(csvColumns), len(row))
+	}
+
+	id, err := strconv.Atoi(row[0])
+	if err != nil {
+		return Task{}, fmt.Errorf("invalid id %q: %v", row[0], err)
+	}
+	var retention time.Duration
+	if row[8] != "" {
+		retention, err = time.ParseDuration(row[8])
+		if err != nil {
+			return Task{}, fmt.Errorf("invalid retention %q: %v", row[8], err)
+		}
+	}
+	created, err := time.Parse(time.RFC3339, row[11])
+	if err != nil {
+		return Task{}, fmt.Errorf("invalid created_at %q: %v", row[11], err)
+	}
+	updated, err := time.Parse(time.RFC3339, row[12])
+	if err != nil {
+		return Task{}, fmt.Errorf("invalid updated_at %q: %v", row[12], err)
+	}
+
+	t := Task{
+		ID:           id,
+		Description:  row[1],
+		Status:       row[2],
+		Priority:     row[3],
+		Tags:         parseTags(row[5]),
+		Dependencies: splitIDs(row[6]),
+		Retention:    retention,
+		LocalID:      row[9],
+		RemoteID:     row[10],
+		CreatedAt:    created,
+		UpdatedAt:    updated,
+		Recurrence:   row[13],
+	}
+	if row[4] != "" {
+		due, err := time.Parse(time.RFC3339, row[4])
+		if err != nil {
+			return Task{}, fmt.Errorf("invalid due_date %q: %v", row[4], err)
+		}
+		t.DueDate = &due
+	}
+	if row[7] != "" {
+		completed, err := time.Parse(time.RFC3339, row[7])
+		if err != nil {
+			return Task{}, fmt.Errorf("invalid completed_at %q: %v", row[7], err)
+		}
+		t.CompletedAt = &completed
+	}
+	return t, nil
+}
+
+// readJSONTasks parses a JSON Lines file written by `export --format json`.
+func readJSONTasks(path string) ([]Task, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("error opening import file: %v", err)
+	}
+	defer f.Close()
+
+	var tasks []Task
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		var t Task
+		if err := json.Unmarshal([]byte(line), &t); err != nil {
+			return nil, fmt.Errorf("error parsing JSON line: %v", err)
+		}
+		tasks = append(tasks, t)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading import file: %v", err)
+	}
+	return tasks, nil
+}
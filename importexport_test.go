@@ -0,0 +1,84 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTaskCSVRoundTrip(t *testing.T) {
+	due := time.Date(2026, time.August, 1, 0, 0, 0, 0, time.UTC)
+	completed := time.Date(2026, time.July, 20, 12, 0, 0, 0, time.UTC)
+
+	want := Task{
+		ID:           7,
+		Description:  "ship the release",
+		Status:       StatusDone,
+		Priority:     "P1",
+		DueDate:      &due,
+		Tags:         []string{"backend", "urgent"},
+		Dependencies: []int{1, 2},
+		CompletedAt:  &completed,
+		Retention:    48 * time.Hour,
+		LocalID:      "ab12cd34",
+		RemoteID:     "99",
+		CreatedAt:    time.Date(2026, time.July, 1, 9, 0, 0, 0, time.UTC),
+		UpdatedAt:    time.Date(2026, time.July, 20, 12, 0, 0, 0, time.UTC),
+		Recurrence:   "weekly:mon",
+	}
+
+	row := taskToCSVRow(want)
+	if len(row) != len(csvColumns) {
+		t.Fatalf("taskToCSVRow returned %d columns, want %d", len(row), len(csvColumns))
+	}
+
+	got, err := taskFromCSVRow(row)
+	if err != nil {
+		t.Fatalf("taskFromCSVRow: %v", err)
+	}
+
+	if got.ID != want.ID || got.Description != want.Description || got.Status != want.Status ||
+		got.Priority != want.Priority || got.Retention != want.Retention ||
+		got.LocalID != want.LocalID || got.RemoteID != want.RemoteID || got.Recurrence != want.Recurrence {
+		t.Errorf("taskFromCSVRow round-trip mismatch: got %+v, want %+v", got, want)
+	}
+	if len(got.Tags) != len(want.Tags) || got.Tags[0] != want.Tags[0] || got.Tags[1] != want.Tags[1] {
+		t.Errorf("Tags round-trip mismatch: got %v, want %v", got.Tags, want.Tags)
+	}
+	if len(got.Dependencies) != len(want.Dependencies) {
+		t.Errorf("Dependencies round-trip mismatch: got %v, want %v", got.Dependencies, want.Dependencies)
+	}
+	if got.DueDate == nil || !got.DueDate.Equal(*want.DueDate) {
+		t.Errorf("DueDate round-trip mismatch: got %v, want %v", got.DueDate, want.DueDate)
+	}
+	if got.CompletedAt == nil || !got.CompletedAt.Equal(*want.CompletedAt) {
+		t.Errorf("CompletedAt round-trip mismatch: got %v, want %v", got.CompletedAt, want.CompletedAt)
+	}
+	if !got.CreatedAt.Equal(want.CreatedAt) || !got.UpdatedAt.Equal(want.UpdatedAt) {
+		t.Errorf("timestamps round-trip mismatch: got %v/%v, want %v/%v",
+			got.CreatedAt, got.UpdatedAt, want.CreatedAt, want.UpdatedAt)
+	}
+}
+
+func TestTaskFromCSVRowWrongColumnCount(t *testing.T) {
+	if _, err := taskFromCSVRow([]string{"1", "2"}); err == nil {
+		t.Error("expected an error for a short row, got nil")
+	}
+}
+
+func TestTaskFromCSVRowEmptyOptionalFields(t *testing.T) {
+	row := taskToCSVRow(Task{
+		ID:          1,
+		Description: "minimal task",
+		Status:      StatusTodo,
+		CreatedAt:   time.Date(2026, time.July, 1, 0, 0, 0, 0, time.UTC),
+		UpdatedAt:   time.Date(2026, time.July, 1, 0, 0, 0, 0, time.UTC),
+	})
+
+	got, err := taskFromCSVRow(row)
+	if err != nil {
+		t.Fatalf("taskFromCSVRow: %v", err)
+	}
+	if got.DueDate != nil || got.CompletedAt != nil {
+		t.Errorf("expected nil DueDate/CompletedAt for a task with none set, got %v/%v", got.DueDate, got.CompletedAt)
+	}
+}
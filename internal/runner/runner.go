@@ -0,0 +1,88 @@
+// Package runner provides a reusable harness for long-running CLI actions
+// (bulk import/export, archive sweeps, sync pulls): it renders a progress
+// bar when stderr is a TTY, installs a SIGINT/SIGTERM handler so an
+// in-flight action can wind down instead of being killed mid-write, and
+// guarantees a caller-supplied flush runs exactly once before Run returns.
+package runner
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/cheggaaa/pb/v3"
+	"golang.org/x/term"
+)
+
+// Options controls how a Run reports progress.
+type Options struct {
+	// Silent suppresses all non-error output, including the progress bar.
+	Silent bool
+	// NoProgress disables the progress bar even when stderr is a TTY.
+	NoProgress bool
+}
+
+// Step processes the i'th of Run's total units of work. It should check
+// ctx and return promptly once it's done so Run can flush and exit.
+type Step func(ctx context.Context, i int) error
+
+// Flush persists whatever partial progress a run made. Run calls it exactly
+// once, whether the run completed, failed, or was interrupted.
+type Flush func() error
+
+// Run calls step once for each of total units of work, showing a progress
+// bar on stderr unless opts disables it, and calls flush before returning.
+// A SIGINT or SIGTERM stops the loop after the in-flight step returns
+// instead of killing the process, so flush always sees consistent state.
+func Run(total int, opts Options, step Step, flush Flush) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(sigCh)
+	go func() {
+		if _, ok := <-sigCh; ok {
+			cancel()
+		}
+	}()
+
+	var bar *pb.ProgressBar
+	if !opts.Silent && !opts.NoProgress && term.IsTerminal(int(os.Stderr.Fd())) {
+		bar = pb.New(total)
+		bar.SetWriter(os.Stderr)
+		bar.Start()
+	}
+
+	var runErr error
+loop:
+	for i := 0; i < total; i++ {
+		select {
+		case <-ctx.Done():
+			runErr = fmt.Errorf("interrupted after %d/%d", i, total)
+			break loop
+		default:
+		}
+		if err := step(ctx, i); err != nil {
+			runErr = err
+			break loop
+		}
+		if bar != nil {
+			bar.Increment()
+		}
+	}
+
+	if bar != nil {
+		bar.Finish()
+	}
+
+	if err := flush(); err != nil {
+		if runErr == nil {
+			return err
+		}
+		return fmt.Errorf("%v (flush also failed: %v)", runErr, err)
+	}
+	return runErr
+}
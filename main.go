@@ -1,21 +1,43 @@
 package main
 
 import (
-	"encoding/json"
 	"fmt"
 	"os"
+	"sort"
 	"strconv"
+	"strings"
 	"text/tabwriter"
 	"time"
 )
 
 // Task represents a single task with all required properties
 type Task struct {
-	ID          int       `json:"id"`
-	Description string    `json:"description"`
-	Status      string    `json:"status"`
-	CreatedAt   time.Time `json:"createdAt"`
-	UpdatedAt   time.Time `json:"updatedAt"`
+	ID           int        `json:"id"`
+	Description  string     `json:"description"`
+	Status       string     `json:"status"`
+	CreatedAt    time.Time  `json:"createdAt"`
+	UpdatedAt    time.Time  `json:"updatedAt"`
+	DueDate      *time.Time `json:"dueDate,omitempty"`
+	Priority     string     `json:"priority,omitempty"`
+	Tags         []string   `json:"tags,omitempty"`
+	Dependencies []int      `json:"dependencies,omitempty"`
+
+	CompletedAt *time.Time    `json:"completedAt,omitempty"`
+	Retention   time.Duration `json:"retention,omitempty"`
+
+	// LocalID is a stable identifier assigned the first time a task is
+	// synced, independent of the numeric ID it happens to hold on any one
+	// device. RemoteID is the IMAP UID of the message that last carried it
+	// in a `sync pull`; pushes decide what to re-send by LocalID and the
+	// sync state file, not by RemoteID.
+	LocalID  string `json:"localId,omitempty"`
+	RemoteID string `json:"remoteId,omitempty"`
+
+	// Recurrence is an RRULE-lite schedule ("daily", "weekly:mon,wed",
+	// "monthly:15", "cron:0 9 * * 1-5"); see parseSchedule. A task's next
+	// due date isn't stored separately - it's computed from Recurrence
+	// each time a new instance is spawned (see spawnNextOccurrence).
+	Recurrence string `json:"recurrence,omitempty"`
 }
 
 // TaskList represents the collection of tasks
@@ -28,14 +50,18 @@ const (
 	StatusTodo       = "todo"
 	StatusInProgress = "in-progress"
 	StatusDone       = "done"
+	StatusBlocked    = "blocked"
 	TasksFile        = "tasks.json"
 )
 
-// Global taskList to avoid repeated load/save operations
-var taskList *TaskList
+// dueDateLayout is the expected format for --due and --before values, and the format due dates are shown in.
+const dueDateLayout = "2006-01-02"
 
-// Global dirty flag to track if tasks have been modified
-var dirty bool
+// validPriorities lists the priority levels accepted by --priority, highest first.
+var validPriorities = []string{"P0", "P1", "P2", "P3"}
+
+// Global store, selected at startup via --storage/TASK_STORAGE (see openStorage).
+var store Storage
 
 // fatal prints an error message and exits with code 1
 func fatal(msg string, err error) {
@@ -59,48 +85,82 @@ func requireArgs(min int, usage string) {
 	}
 }
 
-// loadTasks reads tasks from the JSON file
-func loadTasks() (*TaskList, error) {
-	tl := &TaskList{
-		Tasks:  []Task{},
-		NextID: 1,
-	}
-
-	// Check if file exists
-	if _, err := os.Stat(TasksFile); os.IsNotExist(err) {
-		return tl, nil
+// isValidPriority reports whether p is one of the recognized priority levels.
+func isValidPriority(p string) bool {
+	for _, v := range validPriorities {
+		if v == p {
+			return true
+		}
 	}
+	return false
+}
 
-	data, err := os.ReadFile(TasksFile)
-	if err != nil {
-		return nil, fmt.Errorf("error reading tasks file: %v", err)
+// splitArgs separates positional arguments from `--flag value` pairs. Flags
+// are recognized anywhere in the argument list so commands can keep their
+// existing positional usage (e.g. `add "desc" --priority P1`). A flag that is
+// last or followed by another flag gets an empty value; any other flag takes
+// the next argument as its value.
+func splitArgs(args []string) (positional []string, flags map[string]string) {
+	flags = map[string]string{}
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if strings.HasPrefix(arg, "--") {
+			name := strings.TrimPrefix(arg, "--")
+			if i+1 < len(args) && !strings.HasPrefix(args[i+1], "--") {
+				flags[name] = args[i+1]
+				i++
+			} else {
+				flags[name] = ""
+			}
+			continue
+		}
+		positional = append(positional, arg)
 	}
+	return positional, flags
+}
 
-	if len(data) == 0 {
-		return tl, nil
+// extractStorageFlag pulls the first `--storage <spec>`, wherever it appears, out of args so the
+// backend can be selected before the command/subcommand flags are parsed.
+// It returns args with the flag removed, along with the spec (empty if the
+// flag wasn't present).
+func extractStorageFlag(args []string) (rest []string, spec string) {
+	for i, arg := range args {
+		if arg != "--storage" {
+			continue
+		}
+		rest = append(rest, args[:i]...)
+		if i+1 < len(args) {
+			spec = args[i+1]
+			rest = append(rest, args[i+2:]...)
+		}
+		return rest, spec
 	}
+	return args, ""
+}
 
-	err = json.Unmarshal(data, tl)
-	if err != nil {
-		return nil, fmt.Errorf("error parsing tasks file: %v", err)
+// withProjectTag appends a "project:<name>" tag for --project, the
+// convention `list --project` filters on (see listFilter.storageFilter).
+func withProjectTag(tags []string, project string) []string {
+	if project == "" {
+		return tags
 	}
-
-	return tl, nil
+	return append(tags, "project:"+project)
 }
 
-// saveTasks writes tasks to the JSON file
-func saveTasks(taskList *TaskList) error {
-	data, err := json.MarshalIndent(taskList, "", "  ")
-	if err != nil {
-		return fmt.Errorf("error marshaling tasks: %v", err)
+// parseTags splits a comma-separated --tag value into individual tags.
+func parseTags(raw string) []string {
+	if raw == "" {
+		return nil
 	}
-
-	err = os.WriteFile(TasksFile, data, 0644)
-	if err != nil {
-		return fmt.Errorf("error writing tasks file: %v", err)
+	parts := strings.Split(raw, ",")
+	tags := make([]string, 0, len(parts))
+	for _, p := range parts {
+		p = strings.TrimSpace(p)
+		if p != "" {
+			tags = append(tags, p)
+		}
 	}
-
-	return nil
+	return tags
 }
 
 // findTaskByID finds a task by its ID
@@ -113,35 +173,86 @@ func (tl *TaskList) findTaskByID(id int) *Task {
 	return nil
 }
 
-// addTask adds a new task
-func addTask(description string) error {
+// hasTag reports whether the task carries the given tag.
+func (t Task) hasTag(tag string) bool {
+	for _, existing := range t.Tags {
+		if existing == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// isBlocked reports whether any of the task's dependencies has not been
+// completed yet.
+func isBlocked(store Storage, t Task) (bool, error) {
+	for _, depID := range t.Dependencies {
+		dep, err := store.Find(depID)
+		if err != nil {
+			return false, err
+		}
+		if dep != nil && dep.Status != StatusDone {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// displayStatus returns the status shown in the `list` output, reporting
+// "blocked" for unfinished tasks with unresolved dependencies.
+func displayStatus(store Storage, t Task) (string, error) {
+	if t.Status == StatusDone {
+		return t.Status, nil
+	}
+	blocked, err := isBlocked(store, t)
+	if err != nil {
+		return "", err
+	}
+	if blocked {
+		return StatusBlocked, nil
+	}
+	return t.Status, nil
+}
+
+// addTask adds a new task with optional due date, priority, tags and
+// completion retention.
+func addTask(description string, due *time.Time, priority string, tags []string, retain time.Duration) error {
 	now := time.Now()
 	task := Task{
-		ID:          taskList.NextID,
 		Description: description,
 		Status:      StatusTodo,
 		CreatedAt:   now,
 		UpdatedAt:   now,
+		DueDate:     due,
+		Priority:    priority,
+		Tags:        tags,
+		Retention:   retain,
 	}
 
-	taskList.Tasks = append(taskList.Tasks, task)
-	taskList.NextID++
-	dirty = true // Mark as modified
+	created, err := store.Add(task)
+	if err != nil {
+		return err
+	}
 
-	fmt.Printf("Task added successfully (ID: %d)\n", task.ID)
+	fmt.Printf("Task added successfully (ID: %d)\n", created.ID)
 	return nil
 }
 
 // updateTask updates an existing task's description
 func updateTask(id int, description string) error {
-	task := taskList.findTaskByID(id)
+	task, err := store.Find(id)
+	if err != nil {
+		return err
+	}
 	if task == nil {
 		return fmt.Errorf("task with ID %d not found", id)
 	}
 
 	task.Description = description
 	task.UpdatedAt = time.Now()
-	dirty = true // Mark as modified
+	if err := store.Update(*task); err != nil {
+		return err
+	}
 
 	fmt.Printf("Task %d updated successfully\n", id)
 	return nil
@@ -149,82 +260,353 @@ func updateTask(id int, description string) error {
 
 // deleteTask removes a task by ID
 func deleteTask(id int) error {
-	found := false
-	for i, task := range taskList.Tasks {
-		if task.ID == id {
-			taskList.Tasks = append(taskList.Tasks[:i], taskList.Tasks[i+1:]...)
-			found = true
-			break
+	if err := store.Delete(id); err != nil {
+		return err
+	}
+
+	fmt.Printf("Task %d deleted successfully\n", id)
+	return nil
+}
+
+// markTask updates the status of a task. Marking a task done is rejected
+// while it has unresolved dependencies unless force is set. A non-zero
+// retain overrides the task's retention period at completion time.
+func markTask(id int, status string, force bool, retain time.Duration) error {
+	task, err := store.Find(id)
+	if err != nil {
+		return err
+	}
+	if task == nil {
+		return fmt.Errorf("task with ID %d not found", id)
+	}
+
+	if status == StatusDone && !force {
+		blocked, err := isBlocked(store, *task)
+		if err != nil {
+			return err
+		}
+		if blocked {
+			return fmt.Errorf("task %d is blocked by unresolved dependencies (use --force to override)", id)
 		}
 	}
 
-	if !found {
+	task.Status = status
+	task.UpdatedAt = time.Now()
+	if status == StatusDone {
+		now := time.Now()
+		task.CompletedAt = &now
+		if retain > 0 {
+			task.Retention = retain
+		}
+	}
+
+	if err := store.Update(*task); err != nil {
+		return err
+	}
+
+	fmt.Printf("Task %d marked as %s\n", id, status)
+
+	if status == StatusDone && task.Recurrence != "" {
+		if err := spawnNextOccurrence(*task); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addDependency records that task id is blocked by blockerID.
+func addDependency(id, blockerID int) error {
+	task, err := store.Find(id)
+	if err != nil {
+		return err
+	}
+	if task == nil {
 		return fmt.Errorf("task with ID %d not found", id)
 	}
+	blocker, err := store.Find(blockerID)
+	if err != nil {
+		return err
+	}
+	if blocker == nil {
+		return fmt.Errorf("blocker task with ID %d not found", blockerID)
+	}
+	if id == blockerID {
+		return fmt.Errorf("a task cannot depend on itself")
+	}
+	for _, existing := range task.Dependencies {
+		if existing == blockerID {
+			return fmt.Errorf("task %d already depends on %d", id, blockerID)
+		}
+	}
 
-	dirty = true // Mark as modified
-	fmt.Printf("Task %d deleted successfully\n", id)
+	task.Dependencies = append(task.Dependencies, blockerID)
+	task.UpdatedAt = time.Now()
+	if err := store.Update(*task); err != nil {
+		return err
+	}
+
+	fmt.Printf("Task %d now depends on %d\n", id, blockerID)
 	return nil
 }
 
-// markTask updates the status of a task
-func markTask(id int, status string) error {
-	task := taskList.findTaskByID(id)
+// removeDependency removes a previously recorded dependency.
+func removeDependency(id, blockerID int) error {
+	task, err := store.Find(id)
+	if err != nil {
+		return err
+	}
 	if task == nil {
 		return fmt.Errorf("task with ID %d not found", id)
 	}
 
-	task.Status = status
+	for i, existing := range task.Dependencies {
+		if existing == blockerID {
+			task.Dependencies = append(task.Dependencies[:i], task.Dependencies[i+1:]...)
+			task.UpdatedAt = time.Now()
+			if err := store.Update(*task); err != nil {
+				return err
+			}
+			fmt.Printf("Task %d no longer depends on %d\n", id, blockerID)
+			return nil
+		}
+	}
+
+	return fmt.Errorf("task %d does not depend on %d", id, blockerID)
+}
+
+// addTags attaches tags to a task, skipping any it already carries.
+func addTags(id int, tags []string) error {
+	task, err := store.Find(id)
+	if err != nil {
+		return err
+	}
+	if task == nil {
+		return fmt.Errorf("task with ID %d not found", id)
+	}
+
+	added := 0
+	for _, tag := range tags {
+		if !task.hasTag(tag) {
+			task.Tags = append(task.Tags, tag)
+			added++
+		}
+	}
+	if added == 0 {
+		return fmt.Errorf("task %d already has the given tag(s)", id)
+	}
+
 	task.UpdatedAt = time.Now()
-	dirty = true // Mark as modified
+	if err := store.Update(*task); err != nil {
+		return err
+	}
+	fmt.Printf("Task %d tagged with %s\n", id, strings.Join(tags, ", "))
+	return nil
+}
 
-	fmt.Printf("Task %d marked as %s\n", id, status)
+// removeTags detaches tags from a task.
+func removeTags(id int, tags []string) error {
+	task, err := store.Find(id)
+	if err != nil {
+		return err
+	}
+	if task == nil {
+		return fmt.Errorf("task with ID %d not found", id)
+	}
+
+	remove := map[string]bool{}
+	for _, t := range tags {
+		remove[t] = true
+	}
+
+	kept := task.Tags[:0]
+	for _, existing := range task.Tags {
+		if !remove[existing] {
+			kept = append(kept, existing)
+		}
+	}
+	task.Tags = kept
+
+	task.UpdatedAt = time.Now()
+	if err := store.Update(*task); err != nil {
+		return err
+	}
+	fmt.Printf("Task %d untagged: %s\n", id, strings.Join(tags, ", "))
 	return nil
 }
 
-// listTasks displays tasks based on the specified filter
-func listTasks(filter string) error {
-	if len(taskList.Tasks) == 0 {
-		fmt.Println("No tasks found.")
-		return nil
+// listFilter holds the composable criteria accepted by the `list` command.
+// The fields storage backends can filter on directly are forwarded to
+// Storage.List as a Filter; due window, blocked status and sorting are
+// applied here afterwards.
+type listFilter struct {
+	status    string
+	tag       string
+	priority  string
+	due       string // today|overdue|week
+	blocked   *bool
+	recurring bool
+	sortKeys  []string
+}
+
+// storageFilter extracts the subset of criteria a Storage backend applies directly.
+func (f listFilter) storageFilter() Filter {
+	status := f.status
+	if status == StatusBlocked {
+		status = "" // "blocked" is derived, not a stored status
+	}
+	return Filter{Status: status, Tag: f.tag, Priority: f.priority}
+}
+
+// matchesDerived applies the criteria storageFilter() can't express:
+// blocked status (needs the dependency graph) and due windows (need "now").
+func (f listFilter) matchesDerived(store Storage, t Task) (bool, error) {
+	if f.recurring && t.Recurrence == "" {
+		return false, nil
+	}
+	if f.status == StatusBlocked {
+		blocked, err := isBlocked(store, t)
+		if err != nil {
+			return false, err
+		}
+		if !blocked {
+			return false, nil
+		}
+	}
+	if f.due != "" && !matchesDueWindow(t, f.due) {
+		return false, nil
+	}
+	if f.blocked != nil {
+		blocked, err := isBlocked(store, t)
+		if err != nil {
+			return false, err
+		}
+		if blocked != *f.blocked {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// matchesDueWindow reports whether a task's due date falls within the named
+// window, relative to the current local date.
+func matchesDueWindow(t Task, window string) bool {
+	if t.DueDate == nil {
+		return false
 	}
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	d := t.DueDate.In(time.Local)
+	due := time.Date(d.Year(), d.Month(), d.Day(), 0, 0, 0, 0, time.Local)
+
+	switch window {
+	case "today":
+		return due.Equal(today)
+	case "overdue":
+		return due.Before(today) && t.Status != StatusDone
+	case "week":
+		return !due.Before(today) && due.Before(today.AddDate(0, 0, 7))
+	default:
+		return false
+	}
+}
 
-	// Define filter functions
-	filters := map[string]func(Task) bool{
-		"":               func(t Task) bool { return true },
-		StatusTodo:       func(t Task) bool { return t.Status == StatusTodo },
-		StatusInProgress: func(t Task) bool { return t.Status == StatusInProgress },
-		StatusDone:       func(t Task) bool { return t.Status == StatusDone },
+// priorityRank returns a sort weight for a priority, with unset priorities
+// sorting after all named levels.
+func priorityRank(p string) int {
+	for i, v := range validPriorities {
+		if v == p {
+			return i
+		}
 	}
+	return len(validPriorities)
+}
 
-	match, ok := filters[filter]
-	if !ok {
-		return fmt.Errorf("invalid filter: %s. Valid filters are: %s, %s, %s", filter, StatusTodo, StatusInProgress, StatusDone)
+// sortTasks orders tasks in place according to the requested sort keys,
+// applied in order as tie-breakers (e.g. "priority,due").
+func sortTasks(tasks []Task, keys []string) {
+	sort.SliceStable(tasks, func(i, j int) bool {
+		for _, key := range keys {
+			switch key {
+			case "priority":
+				ri, rj := priorityRank(tasks[i].Priority), priorityRank(tasks[j].Priority)
+				if ri != rj {
+					return ri < rj
+				}
+			case "due":
+				di, dj := tasks[i].DueDate, tasks[j].DueDate
+				if di == nil && dj == nil {
+					continue
+				}
+				if di == nil {
+					return false
+				}
+				if dj == nil {
+					return true
+				}
+				if !di.Equal(*dj) {
+					return di.Before(*dj)
+				}
+			}
+		}
+		return false
+	})
+}
+
+// listTasks displays tasks matching the given filter.
+func listTasks(filter listFilter) error {
+	candidates, err := store.List(filter.storageFilter())
+	if err != nil {
+		return err
+	}
+	if len(candidates) == 0 {
+		fmt.Println("No tasks found.")
+		return nil
 	}
 
-	// Filter and count tasks
 	filteredTasks := []Task{}
-	for _, task := range taskList.Tasks {
-		if match(task) {
+	for _, task := range candidates {
+		ok, err := filter.matchesDerived(store, task)
+		if err != nil {
+			return err
+		}
+		if ok {
 			filteredTasks = append(filteredTasks, task)
 		}
 	}
 
 	if len(filteredTasks) == 0 {
-		if filter != "" {
-			fmt.Printf("No tasks found with status: %s\n", filter)
-		} else {
-			fmt.Println("No tasks found.")
-		}
+		fmt.Println("No tasks found matching the given filters.")
 		return nil
 	}
 
+	if len(filter.sortKeys) > 0 {
+		sortTasks(filteredTasks, filter.sortKeys)
+	}
+
 	// Use tabwriter for pretty output
 	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-	fmt.Fprintln(w, "ID\tStatus\tDescription\tUpdated")
-	fmt.Fprintln(w, "---\t------\t-----------\t-------")
+	fmt.Fprintln(w, "ID\tStatus\tPriority\tDue\tTags\tDescription\tUpdated")
+	fmt.Fprintln(w, "---\t------\t--------\t---\t----\t-----------\t-------")
 	for _, task := range filteredTasks {
-		fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", task.ID, task.Status, task.Description, task.UpdatedAt.Format("2006-01-02 15:04"))
+		due := "-"
+		if task.DueDate != nil {
+			due = task.DueDate.Format(dueDateLayout)
+		}
+		priority := task.Priority
+		if priority == "" {
+			priority = "-"
+		}
+		tags := "-"
+		if len(task.Tags) > 0 {
+			tags = strings.Join(task.Tags, ",")
+		}
+		status, err := displayStatus(store, task)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			task.ID, status, priority, due, tags, task.Description,
+			task.UpdatedAt.Format("2006-01-02 15:04"))
 	}
 	w.Flush()
 
@@ -235,15 +617,31 @@ func listTasks(filter string) error {
 func printUsage() {
 	fmt.Println("Task Tracker CLI")
 	fmt.Println("Usage:")
-	fmt.Println("  task-cli add \"description\"           - Add a new task")
+	fmt.Println("  task-cli [--storage json:tasks.json|sqlite:tasks.db] <command> ...")
+	fmt.Println("                                        - storage also selectable via TASK_STORAGE")
+	fmt.Println("  task-cli add \"description\" [--due YYYY-MM-DD] [--priority P0..P3] [--tag a,b] [--project p] [--retain 168h]")
 	fmt.Println("  task-cli update <id> \"description\"   - Update task description")
 	fmt.Println("  task-cli delete <id>                 - Delete a task")
 	fmt.Println("  task-cli mark-in-progress <id>       - Mark task as in progress")
-	fmt.Println("  task-cli mark-done <id>              - Mark task as done")
-	fmt.Println("  task-cli list                        - List all tasks")
-	fmt.Printf("  task-cli list %s                   - List %s tasks\n", StatusTodo, StatusTodo)
-	fmt.Printf("  task-cli list %s            - List %s tasks\n", StatusInProgress, StatusInProgress)
-	fmt.Printf("  task-cli list %s                   - List %s tasks\n", StatusDone, StatusDone)
+	fmt.Println("  task-cli mark-done <id> [--force] [--retain 24h] - Mark task as done")
+	fmt.Println("  task-cli depends <id> <blocker>      - Add a dependency")
+	fmt.Println("  task-cli undepends <id> <blocker>    - Remove a dependency")
+	fmt.Println("  task-cli tag <id> <tags>             - Add comma-separated tags")
+	fmt.Println("  task-cli untag <id> <tags>           - Remove comma-separated tags")
+	fmt.Println("  task-cli list [status] [--tag t] [--priority P1] [--due today|overdue|week]")
+	fmt.Println("                [--project p] [--blocked] [--unblocked] [--recurring] [--sort priority,due]")
+	fmt.Println("                                        - --project is sugar for --tag project:p; add/recur add")
+	fmt.Println("                                          accept the same --project flag to set it")
+	fmt.Println("  task-cli list --archived             - List archived tasks")
+	fmt.Println("  task-cli recur add --schedule daily|weekly:mon,wed|monthly:15|\"cron:0 9 * * 1-5\" \"description\"")
+	fmt.Println("                [--priority P0..P3] [--tag a,b] [--project p]")
+	fmt.Println("  task-cli archive                     - Move retained-out completed tasks to", ArchiveFile)
+	fmt.Println("  task-cli restore <id>                - Restore an archived task")
+	fmt.Println("  task-cli purge --before YYYY-MM-DD    - Permanently delete archived tasks")
+	fmt.Println("  task-cli sync push                   - Push tasks to the mail remote (see", SyncConfigFile, ")")
+	fmt.Println("  task-cli sync pull                   - Pull and merge tasks from the mail remote")
+	fmt.Println("  task-cli export --format csv|json|md [--no-progress] [--silent] > out.csv")
+	fmt.Println("  task-cli import <file.csv|file.json> [--no-progress] [--silent]")
 }
 
 // Command type for extensible command handling
@@ -253,10 +651,37 @@ type CommandFunc func([]string) error
 func getCommands() map[string]CommandFunc {
 	return map[string]CommandFunc{
 		"add": func(args []string) error {
-			if len(args) < 1 {
+			positional, flags := splitArgs(args)
+			if len(positional) < 1 {
 				return fmt.Errorf("description is required for add command")
 			}
-			return addTask(args[0])
+
+			var due *time.Time
+			if raw, ok := flags["due"]; ok {
+				parsed, err := time.ParseInLocation(dueDateLayout, raw, time.Local)
+				if err != nil {
+					return fmt.Errorf("invalid --due value %q: %v", raw, err)
+				}
+				due = &parsed
+			}
+
+			priority := flags["priority"]
+			if priority != "" && !isValidPriority(priority) {
+				return fmt.Errorf("invalid --priority value %q, expected one of %s", priority, strings.Join(validPriorities, ", "))
+			}
+
+			tags := withProjectTag(parseTags(flags["tag"]), flags["project"])
+
+			var retain time.Duration
+			if raw, ok := flags["retain"]; ok {
+				parsed, err := time.ParseDuration(raw)
+				if err != nil {
+					return fmt.Errorf("invalid --retain value %q: %v", raw, err)
+				}
+				retain = parsed
+			}
+
+			return addTask(positional[0], due, priority, tags, retain)
 		},
 		"update": func(args []string) error {
 			if len(args) < 2 {
@@ -286,55 +711,227 @@ func getCommands() map[string]CommandFunc {
 			if err != nil {
 				return fmt.Errorf("invalid task ID: %s", args[0])
 			}
-			return markTask(id, StatusInProgress)
+			return markTask(id, StatusInProgress, false, 0)
 		},
 		"mark-done": func(args []string) error {
-			if len(args) < 1 {
+			positional, flags := splitArgs(args)
+			if len(positional) < 1 {
 				return fmt.Errorf("ID is required for mark-done command")
 			}
+			id, err := parseID(positional[0])
+			if err != nil {
+				return fmt.Errorf("invalid task ID: %s", positional[0])
+			}
+			_, force := flags["force"]
+
+			var retain time.Duration
+			if raw, ok := flags["retain"]; ok {
+				parsed, err := time.ParseDuration(raw)
+				if err != nil {
+					return fmt.Errorf("invalid --retain value %q: %v", raw, err)
+				}
+				retain = parsed
+			}
+
+			return markTask(id, StatusDone, force, retain)
+		},
+		"depends": func(args []string) error {
+			if len(args) < 2 {
+				return fmt.Errorf("ID and blocker ID are required for depends command")
+			}
+			id, err := parseID(args[0])
+			if err != nil {
+				return fmt.Errorf("invalid task ID: %s", args[0])
+			}
+			blockerID, err := parseID(args[1])
+			if err != nil {
+				return fmt.Errorf("invalid blocker ID: %s", args[1])
+			}
+			return addDependency(id, blockerID)
+		},
+		"undepends": func(args []string) error {
+			if len(args) < 2 {
+				return fmt.Errorf("ID and blocker ID are required for undepends command")
+			}
+			id, err := parseID(args[0])
+			if err != nil {
+				return fmt.Errorf("invalid task ID: %s", args[0])
+			}
+			blockerID, err := parseID(args[1])
+			if err != nil {
+				return fmt.Errorf("invalid blocker ID: %s", args[1])
+			}
+			return removeDependency(id, blockerID)
+		},
+		"tag": func(args []string) error {
+			if len(args) < 2 {
+				return fmt.Errorf("ID and tags are required for tag command")
+			}
+			id, err := parseID(args[0])
+			if err != nil {
+				return fmt.Errorf("invalid task ID: %s", args[0])
+			}
+			return addTags(id, parseTags(args[1]))
+		},
+		"untag": func(args []string) error {
+			if len(args) < 2 {
+				return fmt.Errorf("ID and tags are required for untag command")
+			}
 			id, err := parseID(args[0])
 			if err != nil {
 				return fmt.Errorf("invalid task ID: %s", args[0])
 			}
-			return markTask(id, StatusDone)
+			return removeTags(id, parseTags(args[1]))
+		},
+		"sync": func(args []string) error {
+			if len(args) < 1 {
+				return fmt.Errorf("usage: task-cli sync push|pull")
+			}
+			switch args[0] {
+			case "push":
+				return syncPush()
+			case "pull":
+				return syncPull()
+			default:
+				return fmt.Errorf("unknown sync subcommand %q, expected push or pull", args[0])
+			}
+		},
+		"recur": func(args []string) error {
+			if len(args) < 1 {
+				return fmt.Errorf("usage: task-cli recur add --schedule <spec> \"description\"")
+			}
+			switch args[0] {
+			case "add":
+				positional, flags := splitArgs(args[1:])
+				if len(positional) < 1 {
+					return fmt.Errorf("description is required for recur add command")
+				}
+				recurrence, ok := flags["schedule"]
+				if !ok {
+					return fmt.Errorf("--schedule is required for recur add command")
+				}
+				priority := flags["priority"]
+				if priority != "" && !isValidPriority(priority) {
+					return fmt.Errorf("invalid --priority value %q, expected one of %s", priority, strings.Join(validPriorities, ", "))
+				}
+				tags := withProjectTag(parseTags(flags["tag"]), flags["project"])
+				return addRecurringTask(positional[0], recurrence, priority, tags)
+			default:
+				return fmt.Errorf("unknown recur subcommand %q, expected add", args[0])
+			}
+		},
+		"export": func(args []string) error {
+			_, flags := splitArgs(args)
+			format, ok := flags["format"]
+			if !ok {
+				return fmt.Errorf("--format is required for export command (csv, json, or md)")
+			}
+			_, silent := flags["silent"]
+			_, noProgress := flags["no-progress"]
+			return exportTasks(os.Stdout, format, silent, noProgress)
+		},
+		"import": func(args []string) error {
+			positional, flags := splitArgs(args)
+			if len(positional) < 1 {
+				return fmt.Errorf("file path is required for import command")
+			}
+			_, silent := flags["silent"]
+			_, noProgress := flags["no-progress"]
+			return importTasks(positional[0], silent, noProgress)
+		},
+		"archive": func(args []string) error {
+			return archiveTasks()
+		},
+		"restore": func(args []string) error {
+			if len(args) < 1 {
+				return fmt.Errorf("ID is required for restore command")
+			}
+			id, err := parseID(args[0])
+			if err != nil {
+				return fmt.Errorf("invalid task ID: %s", args[0])
+			}
+			return restoreTask(id)
+		},
+		"purge": func(args []string) error {
+			_, flags := splitArgs(args)
+			raw, ok := flags["before"]
+			if !ok {
+				return fmt.Errorf("--before is required for purge command")
+			}
+			before, err := time.ParseInLocation(dueDateLayout, raw, time.Local)
+			if err != nil {
+				return fmt.Errorf("invalid --before value %q: %v", raw, err)
+			}
+			return purgeArchive(before)
 		},
 		"list": func(args []string) error {
-			filter := ""
-			if len(args) > 0 {
-				filter = args[0]
+			positional, flags := splitArgs(args)
+
+			if _, ok := flags["archived"]; ok {
+				return listArchivedTasks()
 			}
+
+			filter := listFilter{}
+			if len(positional) > 0 {
+				filter.status = positional[0]
+			}
+			filter.priority = flags["priority"]
+			filter.due = flags["due"]
+
+			if tag, ok := flags["tag"]; ok {
+				filter.tag = tag
+			} else if project, ok := flags["project"]; ok {
+				filter.tag = "project:" + project
+			}
+
+			if _, ok := flags["blocked"]; ok {
+				blocked := true
+				filter.blocked = &blocked
+			} else if _, ok := flags["unblocked"]; ok {
+				unblocked := false
+				filter.blocked = &unblocked
+			}
+
+			if _, ok := flags["recurring"]; ok {
+				filter.recurring = true
+			}
+
+			if sortBy, ok := flags["sort"]; ok && sortBy != "" {
+				filter.sortKeys = strings.Split(sortBy, ",")
+			}
+
 			return listTasks(filter)
 		},
 	}
 }
 func main() {
-	// Load tasks once at startup
+	args, spec := extractStorageFlag(os.Args[1:])
+	if spec == "" {
+		spec = os.Getenv("TASK_STORAGE")
+	}
+
 	var err error
-	taskList, err = loadTasks()
+	store, err = openStorage(spec)
 	if err != nil {
-		fatal("Error loading tasks", err)
+		fatal("Error opening storage", err)
 	}
-
-	// Ensure we save tasks before exit only if modified
 	defer func() {
-		if dirty {
-			if err := saveTasks(taskList); err != nil {
-				fmt.Printf("Warning: Error saving tasks: %v\n", err)
-			}
+		if err := store.Close(); err != nil {
+			fmt.Printf("Warning: Error closing storage: %v\n", err)
 		}
 	}()
 
-	if len(os.Args) < 2 {
+	if len(args) < 1 {
 		printUsage()
 		os.Exit(1)
 	}
 
-	command := os.Args[1]
+	command := args[0]
 	commands := getCommands()
 
 	// Execute command using extensible command system
 	if cmd, ok := commands[command]; ok {
-		if err := cmd(os.Args[2:]); err != nil {
+		if err := cmd(args[1:]); err != nil {
 			fatal("Command failed", err)
 		}
 	} else {
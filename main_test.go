@@ -0,0 +1,130 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWithProjectTag(t *testing.T) {
+	got := withProjectTag([]string{"urgent"}, "backend")
+	want := []string{"urgent", "project:backend"}
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("withProjectTag() = %v, want %v", got, want)
+	}
+
+	if got := withProjectTag([]string{"urgent"}, ""); len(got) != 1 || got[0] != "urgent" {
+		t.Errorf("withProjectTag() with no project = %v, want unchanged", got)
+	}
+}
+
+func TestPriorityRank(t *testing.T) {
+	if priorityRank("P0") >= priorityRank("P1") {
+		t.Errorf("priorityRank(P0) should rank before priorityRank(P1)")
+	}
+	if priorityRank("") <= priorityRank("P3") {
+		t.Errorf("priorityRank(\"\") should rank after every named priority")
+	}
+}
+
+func TestSortTasksByPriorityThenDue(t *testing.T) {
+	early := time.Date(2026, time.July, 1, 0, 0, 0, 0, time.UTC)
+	late := time.Date(2026, time.August, 1, 0, 0, 0, 0, time.UTC)
+
+	tasks := []Task{
+		{Description: "low priority, early due", Priority: "P3", DueDate: &early},
+		{Description: "high priority, late due", Priority: "P0", DueDate: &late},
+		{Description: "high priority, early due", Priority: "P0", DueDate: &early},
+		{Description: "no priority, no due"},
+	}
+
+	sortTasks(tasks, []string{"priority", "due"})
+
+	want := []string{
+		"high priority, early due",
+		"high priority, late due",
+		"low priority, early due",
+		"no priority, no due",
+	}
+	for i, w := range want {
+		if tasks[i].Description != w {
+			t.Errorf("position %d: got %q, want %q", i, tasks[i].Description, w)
+		}
+	}
+}
+
+func TestSortTasksNilDueSortsLast(t *testing.T) {
+	due := time.Date(2026, time.July, 1, 0, 0, 0, 0, time.UTC)
+	tasks := []Task{
+		{Description: "no due"},
+		{Description: "has due", DueDate: &due},
+	}
+
+	sortTasks(tasks, []string{"due"})
+
+	if tasks[0].Description != "has due" || tasks[1].Description != "no due" {
+		t.Errorf("expected task with a due date first, got order %q, %q", tasks[0].Description, tasks[1].Description)
+	}
+}
+
+func TestMatchesDueWindow(t *testing.T) {
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	yesterday := today.AddDate(0, 0, -1)
+	in3Days := today.AddDate(0, 0, 3)
+
+	cases := []struct {
+		name   string
+		task   Task
+		window string
+		want   bool
+	}{
+		{"today matches today", Task{DueDate: &today}, "today", true},
+		{"overdue matches past incomplete", Task{DueDate: &yesterday, Status: StatusTodo}, "overdue", true},
+		{"overdue excludes done", Task{DueDate: &yesterday, Status: StatusDone}, "overdue", false},
+		{"week matches within 7 days", Task{DueDate: &in3Days}, "week", true},
+		{"no due date never matches", Task{}, "today", false},
+	}
+
+	for _, c := range cases {
+		if got := matchesDueWindow(c.task, c.window); got != c.want {
+			t.Errorf("%s: matchesDueWindow() = %v, want %v", c.name, got, c.want)
+		}
+	}
+}
+
+func TestListFilterStorageFilterTreatsBlockedAsDerived(t *testing.T) {
+	f := listFilter{status: StatusBlocked, tag: "work"}
+	sf := f.storageFilter()
+	if sf.Status != "" {
+		t.Errorf("storageFilter().Status = %q, want empty (blocked is derived)", sf.Status)
+	}
+	if sf.Tag != "work" {
+		t.Errorf("storageFilter().Tag = %q, want %q", sf.Tag, "work")
+	}
+}
+
+func TestListFilterMatchesDerivedRecurring(t *testing.T) {
+	f := listFilter{recurring: true}
+
+	store, err := newJSONStorage(t.TempDir() + "/tasks.json")
+	if err != nil {
+		t.Fatalf("newJSONStorage: %v", err)
+	}
+	defer store.Close()
+
+	ok, err := f.matchesDerived(store, Task{Recurrence: "daily"})
+	if err != nil {
+		t.Fatalf("matchesDerived: %v", err)
+	}
+	if !ok {
+		t.Error("expected a recurring task to match a recurring-only filter")
+	}
+
+	ok, err = f.matchesDerived(store, Task{})
+	if err != nil {
+		t.Fatalf("matchesDerived: %v", err)
+	}
+	if ok {
+		t.Error("expected a non-recurring task to be excluded by a recurring-only filter")
+	}
+}
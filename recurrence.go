@@ -0,0 +1,202 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// weekdayNames maps the three-letter abbreviations accepted by
+// `weekly:mon,wed` to their time.Weekday value.
+var weekdayNames = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+// schedule computes the next occurrence of a recurring task's due date
+// after a given instant. All implementations work in t's own location, so
+// a schedule.Next result crossing a DST boundary falls out of time.Date's
+// normalization rather than needing special handling.
+type schedule interface {
+	Next(t time.Time) (time.Time, error)
+}
+
+// parseSchedule parses the RRULE-lite syntax accepted by Task.Recurrence:
+// "daily", "weekly:mon,wed", "monthly:15", or "cron:<5-field expression>".
+func parseSchedule(spec string) (schedule, error) {
+	switch {
+	case spec == "daily":
+		return dailySchedule{}, nil
+	case strings.HasPrefix(spec, "weekly:"):
+		return parseWeeklySchedule(strings.TrimPrefix(spec, "weekly:"))
+	case strings.HasPrefix(spec, "monthly:"):
+		return parseMonthlySchedule(strings.TrimPrefix(spec, "monthly:"))
+	case strings.HasPrefix(spec, "cron:"):
+		cron, err := parseCronExpr(strings.TrimPrefix(spec, "cron:"))
+		if err != nil {
+			return nil, err
+		}
+		return cron, nil
+	default:
+		return nil, fmt.Errorf("unrecognized recurrence schedule %q, expected daily, weekly:<days>, monthly:<day> or cron:<expr>", spec)
+	}
+}
+
+// dailySchedule recurs every day at midnight local time, matching how
+// --due is parsed elsewhere in the tool.
+type dailySchedule struct{}
+
+func (dailySchedule) Next(t time.Time) (time.Time, error) {
+	return midnightAfter(t, 1), nil
+}
+
+// weeklySchedule recurs on a fixed set of weekdays.
+type weeklySchedule struct {
+	days []time.Weekday
+}
+
+func parseWeeklySchedule(raw string) (weeklySchedule, error) {
+	var days []time.Weekday
+	for _, name := range strings.Split(raw, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		day, ok := weekdayNames[name]
+		if !ok {
+			return weeklySchedule{}, fmt.Errorf("invalid weekday %q, expected one of sun,mon,tue,wed,thu,fri,sat", name)
+		}
+		days = append(days, day)
+	}
+	if len(days) == 0 {
+		return weeklySchedule{}, fmt.Errorf("weekly schedule requires at least one weekday")
+	}
+	return weeklySchedule{days: days}, nil
+}
+
+func (w weeklySchedule) Next(t time.Time) (time.Time, error) {
+	for offset := 1; offset <= 7; offset++ {
+		candidate := midnightAfter(t, offset)
+		for _, day := range w.days {
+			if candidate.Weekday() == day {
+				return candidate, nil
+			}
+		}
+	}
+	return time.Time{}, fmt.Errorf("no matching weekday found")
+}
+
+// monthlySchedule recurs on a fixed day of the month, clamped to the last
+// day of months shorter than that (e.g. "monthly:31" lands on Feb 28/29).
+type monthlySchedule struct {
+	day int
+}
+
+func parseMonthlySchedule(raw string) (monthlySchedule, error) {
+	day, err := strconv.Atoi(raw)
+	if err != nil || day < 1 || day > 31 {
+		return monthlySchedule{}, fmt.Errorf("invalid monthly day %q, expected 1-31", raw)
+	}
+	return monthlySchedule{day: day}, nil
+}
+
+func (m monthlySchedule) Next(t time.Time) (time.Time, error) {
+	loc := t.Location()
+	baseYear, baseMonth := t.Year(), int(t.Month())
+	for i := 0; i < 12; i++ {
+		year, month := baseYear, time.Month(baseMonth+i)
+		for month > time.December {
+			month -= 12
+			year++
+		}
+		day := m.day
+		if last := lastDayOfMonth(year, month, loc); day > last {
+			day = last
+		}
+		candidate := time.Date(year, month, day, 0, 0, 0, 0, loc)
+		if candidate.After(t) {
+			return candidate, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("no matching day found within 12 months")
+}
+
+// lastDayOfMonth returns the number of days in the given month.
+func lastDayOfMonth(year int, month time.Month, loc *time.Location) int {
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, loc).Day()
+}
+
+// midnightAfter returns midnight, offset days after t's own date, in t's location.
+func midnightAfter(t time.Time, offsetDays int) time.Time {
+	loc := t.Location()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc).AddDate(0, 0, offsetDays)
+}
+
+// addRecurringTask creates the first instance of a recurring task: its due
+// date is the schedule's first occurrence after now.
+func addRecurringTask(description, recurrence, priority string, tags []string) error {
+	sched, err := parseSchedule(recurrence)
+	if err != nil {
+		return err
+	}
+	due, err := sched.Next(time.Now())
+	if err != nil {
+		return fmt.Errorf("computing first occurrence: %v", err)
+	}
+
+	now := time.Now()
+	task := Task{
+		Description: description,
+		Status:      StatusTodo,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+		DueDate:     &due,
+		Priority:    priority,
+		Tags:        tags,
+		Recurrence:  recurrence,
+	}
+
+	created, err := store.Add(task)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Recurring task added successfully (ID: %d, next due %s)\n", created.ID, due.Format(dueDateLayout))
+	return nil
+}
+
+// spawnNextOccurrence creates the next pending instance of a completed
+// recurring task, advancing its due date via its schedule. The completed
+// instance itself is left in place (marked done, subject to the normal
+// archive/retention flow) rather than being deleted.
+func spawnNextOccurrence(completed Task) error {
+	sched, err := parseSchedule(completed.Recurrence)
+	if err != nil {
+		return err
+	}
+
+	from := time.Now()
+	if completed.DueDate != nil && completed.DueDate.After(from) {
+		from = *completed.DueDate
+	}
+	due, err := sched.Next(from)
+	if err != nil {
+		return fmt.Errorf("computing next occurrence: %v", err)
+	}
+
+	now := time.Now()
+	next := Task{
+		Description: completed.Description,
+		Status:      StatusTodo,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+		DueDate:     &due,
+		Priority:    completed.Priority,
+		Tags:        append([]string(nil), completed.Tags...),
+		Recurrence:  completed.Recurrence,
+	}
+
+	created, err := store.Add(next)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Next occurrence scheduled (ID: %d, due %s)\n", created.ID, due.Format(dueDateLayout))
+	return nil
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseScheduleDaily(t *testing.T) {
+	sched, err := parseSchedule("daily")
+	if err != nil {
+		t.Fatalf("parseSchedule: %v", err)
+	}
+
+	from := time.Date(2026, time.July, 29, 15, 30, 0, 0, time.UTC)
+	got, err := sched.Next(from)
+	if err != nil {
+		t.Fatalf("Next: %v", err)
+	}
+	want := time.Date(2026, time.July, 30, 0, 0, 0, 0, time.UTC)
+	if !got.Equal(want) {
+		t.Errorf("Next(%v) = %v, want %v", from, got, want)
+	}
+}
+
+func TestParseScheduleWeekly(t *testing.T) {
+	sched, err := parseSchedule("weekly:mon,wed")
+	if err != nil {
+		t.Fatalf("parseSchedule: %v", err)
+	}
+
+	from := time.Date(2026, time.July, 29, 0, 0, 0, 0, time.UTC) // Wednesday
+	got, err := sched.Next(from)
+	if err != nil {
+		t.Fatalf("Next: %v", err)
+	}
+	want := time.Date(2026, time.August, 3, 0, 0, 0, 0, time.UTC) // next Monday
+	if !got.Equal(want) {
+		t.Errorf("Next(%v) = %v, want %v", from, got, want)
+	}
+}
+
+func TestParseScheduleWeeklyInvalidDay(t *testing.T) {
+	if _, err := parseSchedule("weekly:funday"); err == nil {
+		t.Error("expected an error for an invalid weekday, got nil")
+	}
+}
+
+func TestParseScheduleMonthlyClampsShortMonth(t *testing.T) {
+	sched, err := parseSchedule("monthly:31")
+	if err != nil {
+		t.Fatalf("parseSchedule: %v", err)
+	}
+
+	from := time.Date(2026, time.January, 31, 0, 0, 0, 0, time.UTC)
+	got, err := sched.Next(from)
+	if err != nil {
+		t.Fatalf("Next: %v", err)
+	}
+	want := time.Date(2026, time.February, 28, 0, 0, 0, 0, time.UTC)
+	if !got.Equal(want) {
+		t.Errorf("Next(%v) = %v, want %v", from, got, want)
+	}
+}
+
+func TestParseScheduleUnrecognized(t *testing.T) {
+	if _, err := parseSchedule("yearly"); err == nil {
+		t.Error("expected an error for an unrecognized schedule, got nil")
+	}
+}
+
+func TestParseScheduleCronDelegates(t *testing.T) {
+	sched, err := parseSchedule("cron:0 9 * * *")
+	if err != nil {
+		t.Fatalf("parseSchedule: %v", err)
+	}
+	if _, ok := sched.(*cronSchedule); !ok {
+		t.Errorf("parseSchedule(%q) = %T, want *cronSchedule", "cron:0 9 * * *", sched)
+	}
+}
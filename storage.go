@@ -0,0 +1,75 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Filter holds the criteria a Storage backend can apply directly against
+// its stored columns. "due" windows and "blocked" status are evaluated by
+// the caller instead (see listTasks) since they depend on the current time
+// and the dependency graph rather than a single column.
+type Filter struct {
+	Status   string
+	Tag      string
+	Priority string
+}
+
+// matches reports whether a task satisfies every criterion in the filter.
+// jsonStorage uses this directly; sqliteStorage translates the same fields
+// into a SQL WHERE clause instead.
+func (f Filter) matches(t Task) bool {
+	if f.Status != "" && t.Status != f.Status {
+		return false
+	}
+	if f.Tag != "" && !t.hasTag(f.Tag) {
+		return false
+	}
+	if f.Priority != "" && t.Priority != f.Priority {
+		return false
+	}
+	return true
+}
+
+// Storage abstracts task persistence so the CLI can run against either the
+// flat tasks.json file or a SQLite database, selected via --storage or the
+// TASK_STORAGE environment variable (see openStorage).
+type Storage interface {
+	// Add assigns the task a new ID and persists it.
+	Add(task Task) (Task, error)
+	// Update persists changes to a task that already exists.
+	Update(task Task) error
+	// Delete removes a task by ID.
+	Delete(id int) error
+	// Find looks up a task by ID, returning (nil, nil) if it doesn't exist.
+	Find(id int) (*Task, error)
+	// List returns every task matching filter.
+	List(filter Filter) ([]Task, error)
+	// Restore inserts a task preserving its existing ID. Used by `restore`
+	// and `sync pull`, where the ID must stay stable across a round trip.
+	Restore(task Task) error
+	// Close releases any resources held by the backend.
+	Close() error
+}
+
+// openStorage opens the backend named by spec, in "backend:path" form
+// (e.g. "json:tasks.json" or "sqlite:tasks.db"). An empty backend name
+// defaults to json.
+func openStorage(spec string) (Storage, error) {
+	backend, path, hasPath := strings.Cut(spec, ":")
+
+	switch backend {
+	case "", "json":
+		if !hasPath || path == "" {
+			path = TasksFile
+		}
+		return newJSONStorage(path)
+	case "sqlite":
+		if !hasPath || path == "" {
+			return nil, fmt.Errorf("sqlite storage requires a path, e.g. --storage sqlite:tasks.db")
+		}
+		return newSQLiteStorage(path)
+	default:
+		return nil, fmt.Errorf("unknown storage backend %q", backend)
+	}
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// jsonStorage is the original flat-file backend: every mutation re-marshals
+// and rewrites the whole file, same as the tool has always done.
+type jsonStorage struct {
+	path string
+	tl   *TaskList
+}
+
+// newJSONStorage loads (or initializes) the task list at path.
+func newJSONStorage(path string) (*jsonStorage, error) {
+	tl := &TaskList{Tasks: []Task{}, NextID: 1}
+
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return &jsonStorage{path: path, tl: tl}, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading tasks file: %v", err)
+	}
+	if len(data) == 0 {
+		return &jsonStorage{path: path, tl: tl}, nil
+	}
+	if err := json.Unmarshal(data, tl); err != nil {
+		return nil, fmt.Errorf("error parsing tasks file: %v", err)
+	}
+
+	return &jsonStorage{path: path, tl: tl}, nil
+}
+
+// save writes the whole task list back to disk.
+func (s *jsonStorage) save() error {
+	data, err := json.MarshalIndent(s.tl, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error marshaling tasks: %v", err)
+	}
+	if err := os.WriteFile(s.path, data, 0644); err != nil {
+		return fmt.Errorf("error writing tasks file: %v", err)
+	}
+	return nil
+}
+
+func (s *jsonStorage) Add(task Task) (Task, error) {
+	task.ID = s.tl.NextID
+	s.tl.Tasks = append(s.tl.Tasks, task)
+	s.tl.NextID++
+	if err := s.save(); err != nil {
+		return Task{}, err
+	}
+	return task, nil
+}
+
+func (s *jsonStorage) Update(task Task) error {
+	existing := s.tl.findTaskByID(task.ID)
+	if existing == nil {
+		return fmt.Errorf("task with ID %d not found", task.ID)
+	}
+	*existing = task
+	return s.save()
+}
+
+func (s *jsonStorage) Delete(id int) error {
+	for i, t := range s.tl.Tasks {
+		if t.ID == id {
+			s.tl.Tasks = append(s.tl.Tasks[:i], s.tl.Tasks[i+1:]...)
+			return s.save()
+		}
+	}
+	return fmt.Errorf("task with ID %d not found", id)
+}
+
+func (s *jsonStorage) Find(id int) (*Task, error) {
+	t := s.tl.findTaskByID(id)
+	if t == nil {
+		return nil, nil
+	}
+	found := *t
+	return &found, nil
+}
+
+func (s *jsonStorage) List(filter Filter) ([]Task, error) {
+	var out []Task
+	for _, t := range s.tl.Tasks {
+		if filter.matches(t) {
+			out = append(out, t)
+		}
+	}
+	return out, nil
+}
+
+func (s *jsonStorage) Restore(task Task) error {
+	if s.tl.findTaskByID(task.ID) != nil {
+		return fmt.Errorf("task with ID %d already exists", task.ID)
+	}
+	s.tl.Tasks = append(s.tl.Tasks, task)
+	if task.ID >= s.tl.NextID {
+		s.tl.NextID = task.ID + 1
+	}
+	return s.save()
+}
+
+func (s *jsonStorage) Close() error {
+	return nil // every mutation above already saved eagerly
+}
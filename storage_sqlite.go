@@ -0,0 +1,359 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	_ "modernc.org/sqlite" // pure-Go driver, no CGO required
+)
+
+// sqliteStorage is a Storage backend for larger task lists, where the
+// flat-file jsonStorage's full load/save on every mutation stops scaling.
+// Beyond the core tasks/task_tags columns, the tasks table also stores
+// dependencies as a comma-separated column, completion bookkeeping, the
+// sync IDs and the recurrence so the full Task struct round-trips, and a
+// task_id_seq table keeps the next ID to assign.
+type sqliteStorage struct {
+	db *sql.DB
+}
+
+// newSQLiteStorage opens (creating if necessary) a SQLite database at path
+// and ensures its schema exists.
+func newSQLiteStorage(path string) (*sqliteStorage, error) {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, fmt.Errorf("opening sqlite database: %v", err)
+	}
+
+	schema := []string{
+		`CREATE TABLE IF NOT EXISTS tasks (
+			id INTEGER PRIMARY KEY,
+			description TEXT NOT NULL,
+			status TEXT NOT NULL,
+			created_at TIMESTAMP NOT NULL,
+			updated_at TIMESTAMP NOT NULL,
+			priority TEXT,
+			due_date TIMESTAMP,
+			completed_at TIMESTAMP,
+			retention_ns INTEGER,
+			local_id TEXT,
+			remote_id TEXT,
+			dependencies TEXT,
+			recurrence TEXT
+		)`,
+		`CREATE TABLE IF NOT EXISTS task_tags (
+			task_id INTEGER NOT NULL REFERENCES tasks(id),
+			tag TEXT NOT NULL
+		)`,
+		// task_id_seq tracks the next ID to assign, monotonically - mirroring
+		// jsonStorage's NextID counter - so deleting the highest-ID task (via
+		// `delete` or `archive`) doesn't let a later Add reuse its ID.
+		`CREATE TABLE IF NOT EXISTS task_id_seq (
+			id INTEGER PRIMARY KEY CHECK (id = 1),
+			next_id INTEGER NOT NULL
+		)`,
+	}
+	for _, stmt := range schema {
+		if _, err := db.Exec(stmt); err != nil {
+			db.Close()
+			return nil, fmt.Errorf("creating schema: %v", err)
+		}
+	}
+
+	// Seed the sequence from the highest ID already present, so opening an
+	// existing database (from before task_id_seq existed) picks up where it
+	// left off instead of restarting at 1.
+	if _, err := db.Exec(`INSERT INTO task_id_seq (id, next_id)
+		SELECT 1, COALESCE(MAX(id), 0) + 1 FROM tasks
+		WHERE NOT EXISTS (SELECT 1 FROM task_id_seq WHERE id = 1)`); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("seeding id sequence: %v", err)
+	}
+
+	return &sqliteStorage{db: db}, nil
+}
+
+// joinIDs/splitIDs serialize []int dependency lists into the dependencies
+// column.
+func joinIDs(ids []int) string {
+	parts := make([]string, len(ids))
+	for i, id := range ids {
+		parts[i] = strconv.Itoa(id)
+	}
+	return strings.Join(parts, ",")
+}
+
+func splitIDs(raw string) []int {
+	if raw == "" {
+		return nil
+	}
+	parts := strings.Split(raw, ",")
+	ids := make([]int, 0, len(parts))
+	for _, p := range parts {
+		if id, err := strconv.Atoi(p); err == nil {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// insertTagsTx replaces task_tags rows for a task within tx.
+func insertTagsTx(tx *sql.Tx, taskID int, tags []string) error {
+	if _, err := tx.Exec(`DELETE FROM task_tags WHERE task_id = ?`, taskID); err != nil {
+		return err
+	}
+	for _, tag := range tags {
+		if _, err := tx.Exec(`INSERT INTO task_tags (task_id, tag) VALUES (?, ?)`, taskID, tag); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// insertTx inserts task within tx, leaving the commit to the caller so it
+// can be combined with other writes (e.g. bumping task_id_seq) atomically.
+func (s *sqliteStorage) insertTx(tx *sql.Tx, task Task) error {
+	_, err := tx.Exec(
+		`INSERT INTO tasks (id, description, status, created_at, updated_at, priority, due_date, completed_at, retention_ns, local_id, remote_id, dependencies, recurrence)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		task.ID, task.Description, task.Status, task.CreatedAt, task.UpdatedAt,
+		nullableString(task.Priority), nullableTime(task.DueDate), nullableTime(task.CompletedAt),
+		int64(task.Retention), nullableString(task.LocalID), nullableString(task.RemoteID), joinIDs(task.Dependencies), nullableString(task.Recurrence),
+	)
+	if err != nil {
+		return err
+	}
+	return insertTagsTx(tx, task.ID, task.Tags)
+}
+
+func (s *sqliteStorage) Add(task Task) (Task, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return Task{}, err
+	}
+	defer tx.Rollback()
+
+	var nextID int
+	if err := tx.QueryRow(`SELECT next_id FROM task_id_seq WHERE id = 1`).Scan(&nextID); err != nil {
+		return Task{}, err
+	}
+	if _, err := tx.Exec(`UPDATE task_id_seq SET next_id = ? WHERE id = 1`, nextID+1); err != nil {
+		return Task{}, err
+	}
+	task.ID = nextID
+
+	if err := s.insertTx(tx, task); err != nil {
+		return Task{}, err
+	}
+	if err := tx.Commit(); err != nil {
+		return Task{}, err
+	}
+	return task, nil
+}
+
+// Restore inserts task preserving its existing ID, advancing task_id_seq
+// past it if needed - matching jsonStorage.Restore, so an ID restored from
+// the archive or a sync pull can't collide with a later Add.
+func (s *sqliteStorage) Restore(task Task) error {
+	if existing, err := s.Find(task.ID); err != nil {
+		return err
+	} else if existing != nil {
+		return fmt.Errorf("task with ID %d already exists", task.ID)
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := s.insertTx(tx, task); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`UPDATE task_id_seq SET next_id = ? WHERE id = 1 AND next_id <= ?`, task.ID+1, task.ID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (s *sqliteStorage) Update(task Task) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.Exec(
+		`UPDATE tasks SET description = ?, status = ?, created_at = ?, updated_at = ?, priority = ?,
+		 due_date = ?, completed_at = ?, retention_ns = ?, local_id = ?, remote_id = ?, dependencies = ?, recurrence = ? WHERE id = ?`,
+		task.Description, task.Status, task.CreatedAt, task.UpdatedAt,
+		nullableString(task.Priority), nullableTime(task.DueDate), nullableTime(task.CompletedAt),
+		int64(task.Retention), nullableString(task.LocalID), nullableString(task.RemoteID), joinIDs(task.Dependencies), nullableString(task.Recurrence), task.ID,
+	)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("task with ID %d not found", task.ID)
+	}
+	if err := insertTagsTx(tx, task.ID, task.Tags); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (s *sqliteStorage) Delete(id int) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.Exec(`DELETE FROM tasks WHERE id = ?`, id)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("task with ID %d not found", id)
+	}
+	if _, err := tx.Exec(`DELETE FROM task_tags WHERE task_id = ?`, id); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (s *sqliteStorage) Find(id int) (*Task, error) {
+	task, err := s.scanTask(s.db.QueryRow(
+		`SELECT id, description, status, created_at, updated_at, priority, due_date, completed_at, retention_ns, local_id, remote_id, dependencies, recurrence
+		 FROM tasks WHERE id = ?`, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := s.loadTags(task); err != nil {
+		return nil, err
+	}
+	return task, nil
+}
+
+// List translates filter into a WHERE clause over the tasks table; see
+// Filter's doc comment for why due/blocked filtering happens in the caller
+// instead.
+func (s *sqliteStorage) List(filter Filter) ([]Task, error) {
+	query := `SELECT DISTINCT t.id, t.description, t.status, t.created_at, t.updated_at, t.priority, t.due_date,
+		t.completed_at, t.retention_ns, t.local_id, t.remote_id, t.dependencies, t.recurrence FROM tasks t`
+	var conds []string
+	var args []interface{}
+
+	if filter.Tag != "" {
+		query += ` JOIN task_tags tg ON tg.task_id = t.id`
+		conds = append(conds, `tg.tag = ?`)
+		args = append(args, filter.Tag)
+	}
+	if filter.Status != "" {
+		conds = append(conds, `t.status = ?`)
+		args = append(args, filter.Status)
+	}
+	if filter.Priority != "" {
+		conds = append(conds, `t.priority = ?`)
+		args = append(args, filter.Priority)
+	}
+	if len(conds) > 0 {
+		query += " WHERE " + strings.Join(conds, " AND ")
+	}
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tasks []Task
+	for rows.Next() {
+		task, err := s.scanTask(rows)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.loadTags(task); err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, *task)
+	}
+	return tasks, rows.Err()
+}
+
+func (s *sqliteStorage) Close() error {
+	return s.db.Close()
+}
+
+// rowScanner is satisfied by both *sql.Row and *sql.Rows.
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func (s *sqliteStorage) scanTask(row rowScanner) (*Task, error) {
+	var task Task
+	var priority, localID, remoteID, recurrence sql.NullString
+	var dueDate, completedAt sql.NullTime
+	var retentionNS sql.NullInt64
+	var dependencies string
+
+	err := row.Scan(&task.ID, &task.Description, &task.Status, &task.CreatedAt, &task.UpdatedAt,
+		&priority, &dueDate, &completedAt, &retentionNS, &localID, &remoteID, &dependencies, &recurrence)
+	if err != nil {
+		return nil, err
+	}
+
+	task.Priority = priority.String
+	task.LocalID = localID.String
+	task.RemoteID = remoteID.String
+	task.Retention = time.Duration(retentionNS.Int64)
+	task.Dependencies = splitIDs(dependencies)
+	task.Recurrence = recurrence.String
+	if dueDate.Valid {
+		due := dueDate.Time
+		task.DueDate = &due
+	}
+	if completedAt.Valid {
+		completed := completedAt.Time
+		task.CompletedAt = &completed
+	}
+	return &task, nil
+}
+
+func (s *sqliteStorage) loadTags(task *Task) error {
+	rows, err := s.db.Query(`SELECT tag FROM task_tags WHERE task_id = ?`, task.ID)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	task.Tags = nil
+	for rows.Next() {
+		var tag string
+		if err := rows.Scan(&tag); err != nil {
+			return err
+		}
+		task.Tags = append(task.Tags, tag)
+	}
+	return rows.Err()
+}
+
+func nullableString(s string) interface{} {
+	if s == "" {
+		return nil
+	}
+	return s
+}
+
+func nullableTime(t *time.Time) interface{} {
+	if t == nil {
+		return nil
+	}
+	return *t
+}
@@ -0,0 +1,241 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+// storageBackends returns a fresh instance of every Storage implementation,
+// each rooted in its own temp dir, so the same test body can run against
+// both without either seeing the other's data.
+func storageBackends(t *testing.T) map[string]Storage {
+	t.Helper()
+	dir := t.TempDir()
+
+	jsonStore, err := newJSONStorage(dir + "/tasks.json")
+	if err != nil {
+		t.Fatalf("newJSONStorage: %v", err)
+	}
+	sqliteStore, err := newSQLiteStorage(dir + "/tasks.db")
+	if err != nil {
+		t.Fatalf("newSQLiteStorage: %v", err)
+	}
+
+	backends := map[string]Storage{"json": jsonStore, "sqlite": sqliteStore}
+	t.Cleanup(func() {
+		for _, s := range backends {
+			s.Close()
+		}
+	})
+	return backends
+}
+
+func TestStorageAddFindList(t *testing.T) {
+	for name, store := range storageBackends(t) {
+		t.Run(name, func(t *testing.T) {
+			now := time.Date(2026, time.July, 1, 0, 0, 0, 0, time.UTC)
+			created, err := store.Add(Task{
+				Description: "write docs",
+				Status:      StatusTodo,
+				Priority:    "P1",
+				Tags:        []string{"docs", "writing"},
+				CreatedAt:   now,
+				UpdatedAt:   now,
+			})
+			if err != nil {
+				t.Fatalf("Add: %v", err)
+			}
+			if created.ID == 0 {
+				t.Fatalf("Add did not assign an ID")
+			}
+
+			found, err := store.Find(created.ID)
+			if err != nil {
+				t.Fatalf("Find: %v", err)
+			}
+			if found == nil {
+				t.Fatalf("Find(%d) = nil, want the task just added", created.ID)
+			}
+			if found.Description != "write docs" || len(found.Tags) != 2 {
+				t.Errorf("Find(%d) = %+v, want matching description and tags", created.ID, found)
+			}
+
+			list, err := store.List(Filter{Tag: "docs"})
+			if err != nil {
+				t.Fatalf("List: %v", err)
+			}
+			if len(list) != 1 || list[0].ID != created.ID {
+				t.Errorf("List(Filter{Tag: docs}) = %+v, want just the task just added", list)
+			}
+
+			list, err = store.List(Filter{Tag: "nonexistent"})
+			if err != nil {
+				t.Fatalf("List: %v", err)
+			}
+			if len(list) != 0 {
+				t.Errorf("List(Filter{Tag: nonexistent}) = %+v, want empty", list)
+			}
+		})
+	}
+}
+
+func TestStorageFindMissingReturnsNilNotError(t *testing.T) {
+	for name, store := range storageBackends(t) {
+		t.Run(name, func(t *testing.T) {
+			found, err := store.Find(9999)
+			if err != nil {
+				t.Fatalf("Find: %v", err)
+			}
+			if found != nil {
+				t.Errorf("Find(9999) = %+v, want nil", found)
+			}
+		})
+	}
+}
+
+func TestStorageUpdate(t *testing.T) {
+	for name, store := range storageBackends(t) {
+		t.Run(name, func(t *testing.T) {
+			now := time.Now()
+			created, err := store.Add(Task{Description: "old", Status: StatusTodo, CreatedAt: now, UpdatedAt: now})
+			if err != nil {
+				t.Fatalf("Add: %v", err)
+			}
+
+			created.Description = "new"
+			created.Status = StatusDone
+			if err := store.Update(created); err != nil {
+				t.Fatalf("Update: %v", err)
+			}
+
+			found, err := store.Find(created.ID)
+			if err != nil {
+				t.Fatalf("Find: %v", err)
+			}
+			if found.Description != "new" || found.Status != StatusDone {
+				t.Errorf("after Update, Find(%d) = %+v, want description=new status=done", created.ID, found)
+			}
+		})
+	}
+}
+
+func TestStorageUpdateMissingReturnsError(t *testing.T) {
+	for name, store := range storageBackends(t) {
+		t.Run(name, func(t *testing.T) {
+			if err := store.Update(Task{ID: 9999, Description: "ghost"}); err == nil {
+				t.Error("expected an error updating a task that doesn't exist, got nil")
+			}
+		})
+	}
+}
+
+func TestStorageDelete(t *testing.T) {
+	for name, store := range storageBackends(t) {
+		t.Run(name, func(t *testing.T) {
+			now := time.Now()
+			created, err := store.Add(Task{Description: "temp", Status: StatusTodo, CreatedAt: now, UpdatedAt: now})
+			if err != nil {
+				t.Fatalf("Add: %v", err)
+			}
+
+			if err := store.Delete(created.ID); err != nil {
+				t.Fatalf("Delete: %v", err)
+			}
+
+			found, err := store.Find(created.ID)
+			if err != nil {
+				t.Fatalf("Find: %v", err)
+			}
+			if found != nil {
+				t.Errorf("Find(%d) after Delete = %+v, want nil", created.ID, found)
+			}
+
+			if err := store.Delete(created.ID); err == nil {
+				t.Error("expected an error deleting an already-deleted task, got nil")
+			}
+		})
+	}
+}
+
+func TestStorageRestorePreservesID(t *testing.T) {
+	for name, store := range storageBackends(t) {
+		t.Run(name, func(t *testing.T) {
+			now := time.Now()
+			task := Task{ID: 42, Description: "archived task", Status: StatusDone, CreatedAt: now, UpdatedAt: now}
+
+			if err := store.Restore(task); err != nil {
+				t.Fatalf("Restore: %v", err)
+			}
+
+			found, err := store.Find(42)
+			if err != nil {
+				t.Fatalf("Find: %v", err)
+			}
+			if found == nil || found.ID != 42 {
+				t.Errorf("Find(42) after Restore = %+v, want a task with ID 42", found)
+			}
+
+			if err := store.Restore(task); err == nil {
+				t.Error("expected Restore to fail on an ID collision, got nil")
+			}
+		})
+	}
+}
+
+// TestStorageAddDoesNotReuseDeletedID is the review regression: deleting the
+// highest-ID task (as `archive` does via store.Delete) must not let a
+// subsequent Add hand out that same ID again.
+func TestStorageAddDoesNotReuseDeletedID(t *testing.T) {
+	for name, store := range storageBackends(t) {
+		t.Run(name, func(t *testing.T) {
+			now := time.Now()
+
+			first, err := store.Add(Task{Description: "first", Status: StatusTodo, CreatedAt: now, UpdatedAt: now})
+			if err != nil {
+				t.Fatalf("Add: %v", err)
+			}
+			second, err := store.Add(Task{Description: "second", Status: StatusTodo, CreatedAt: now, UpdatedAt: now})
+			if err != nil {
+				t.Fatalf("Add: %v", err)
+			}
+
+			if err := store.Delete(second.ID); err != nil {
+				t.Fatalf("Delete: %v", err)
+			}
+
+			third, err := store.Add(Task{Description: "third", Status: StatusTodo, CreatedAt: now, UpdatedAt: now})
+			if err != nil {
+				t.Fatalf("Add: %v", err)
+			}
+
+			if third.ID == second.ID {
+				t.Errorf("Add reused deleted ID %d, want a fresh ID (first=%d, second=%d, third=%d)",
+					second.ID, first.ID, second.ID, third.ID)
+			}
+		})
+	}
+}
+
+// TestStorageRestoreAdvancesIDSequence ensures a Restore with a high ID (as
+// `import`/`sync pull` perform) pushes the sequence past it, so a later Add
+// doesn't collide with the restored task.
+func TestStorageRestoreAdvancesIDSequence(t *testing.T) {
+	for name, store := range storageBackends(t) {
+		t.Run(name, func(t *testing.T) {
+			now := time.Now()
+
+			if err := store.Restore(Task{ID: 100, Description: "restored", Status: StatusTodo, CreatedAt: now, UpdatedAt: now}); err != nil {
+				t.Fatalf("Restore: %v", err)
+			}
+
+			added, err := store.Add(Task{Description: "new", Status: StatusTodo, CreatedAt: now, UpdatedAt: now})
+			if err != nil {
+				t.Fatalf("Add: %v", err)
+			}
+
+			if added.ID <= 100 {
+				t.Errorf("Add after Restore(ID:100) returned ID %d, want > 100", added.ID)
+			}
+		})
+	}
+}
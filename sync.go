@@ -0,0 +1,288 @@
+package main
+
+import (
+	"bufio"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	tasksync "github.com/Aryan-Shrivastva/task-tracker/sync"
+)
+
+// SyncConfigFile is the user-level config read by `sync push`/`sync pull`.
+const SyncConfigFile = "~/.task-cli/config.yaml"
+
+// SyncStateFile records, per LocalID, the UpdatedAt of the task as of its
+// last successful push - so `sync push` only re-sends tasks that changed
+// since.
+const SyncStateFile = "tasks.sync-state.json"
+
+// SyncConfig holds the IMAP settings used to sync tasks through an email
+// inbox. It is loaded from a small subset of YAML (flat `key: value` pairs)
+// parsed by hand, without a YAML library.
+type SyncConfig struct {
+	IMAPHost string
+	IMAPPort string
+	Username string
+	Password string
+	Folder   string
+}
+
+// remote builds the transport-layer config the sync package needs from cfg.
+func (cfg SyncConfig) remote() *tasksync.Remote {
+	return tasksync.NewRemote(tasksync.Config{
+		IMAPHost: cfg.IMAPHost,
+		IMAPPort: cfg.IMAPPort,
+		Username: cfg.Username,
+		Password: cfg.Password,
+		Folder:   cfg.Folder,
+	})
+}
+
+// expandHome resolves a leading ~ to the user's home directory.
+func expandHome(path string) (string, error) {
+	if !strings.HasPrefix(path, "~") {
+		return path, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("resolving home directory: %v", err)
+	}
+	return filepath.Join(home, strings.TrimPrefix(path, "~")), nil
+}
+
+// loadSyncConfig reads ~/.task-cli/config.yaml. Only flat `key: value`
+// lines are supported, which is all the sync backend needs.
+func loadSyncConfig() (SyncConfig, error) {
+	cfg := SyncConfig{
+		IMAPPort: "993",
+		Folder:   "TASKS",
+	}
+
+	path, err := expandHome(SyncConfigFile)
+	if err != nil {
+		return cfg, err
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return cfg, fmt.Errorf("reading sync config %s: %v", path, err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		key = strings.TrimSpace(key)
+		value = strings.Trim(strings.TrimSpace(value), `"'`)
+
+		switch key {
+		case "imap_host":
+			cfg.IMAPHost = value
+		case "imap_port":
+			cfg.IMAPPort = value
+		case "username":
+			cfg.Username = value
+		case "password":
+			cfg.Password = value
+		case "folder":
+			cfg.Folder = value
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return cfg, err
+	}
+
+	if cfg.IMAPHost == "" || cfg.Username == "" {
+		return cfg, fmt.Errorf("%s must set at least imap_host and username", path)
+	}
+	return cfg, nil
+}
+
+// syncState maps a task's LocalID to the UpdatedAt it had as of its last
+// successful push, so a push can skip tasks that haven't changed since.
+type syncState map[string]time.Time
+
+// loadSyncState reads tasks.sync-state.json, returning an empty state if it
+// does not exist yet.
+func loadSyncState() (syncState, error) {
+	state := syncState{}
+
+	data, err := os.ReadFile(SyncStateFile)
+	if os.IsNotExist(err) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading sync state file: %v", err)
+	}
+	if len(data) == 0 {
+		return state, nil
+	}
+
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("error parsing sync state file: %v", err)
+	}
+	return state, nil
+}
+
+// saveSyncState writes tasks.sync-state.json.
+func saveSyncState(state syncState) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error marshaling sync state: %v", err)
+	}
+	if err := os.WriteFile(SyncStateFile, data, 0644); err != nil {
+		return fmt.Errorf("error writing sync state file: %v", err)
+	}
+	return nil
+}
+
+// newLocalID generates a short random identifier that stays stable for a
+// task across devices, independent of the numeric ID SQLite/JSON storage
+// assigns it locally.
+func newLocalID() (string, error) {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("generating local id: %v", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// mergeRemoteTask merges a pulled task into the store, keyed by LocalID,
+// using UpdatedAt to resolve conflicts: the newer copy wins. A LocalID with
+// no local match yet is a task seen for the first time, so it is restored
+// under its own numeric ID if free or else re-added.
+func mergeRemoteTask(store Storage, remote Task) error {
+	tasks, err := store.List(Filter{})
+	if err != nil {
+		return err
+	}
+
+	for _, local := range tasks {
+		if local.LocalID != remote.LocalID {
+			continue
+		}
+		if !remote.UpdatedAt.After(local.UpdatedAt) {
+			return nil
+		}
+		remote.ID = local.ID
+		return store.Update(remote)
+	}
+
+	if existing, err := store.Find(remote.ID); err != nil {
+		return err
+	} else if existing == nil {
+		return store.Restore(remote)
+	}
+
+	remote.ID = 0
+	_, err = store.Add(remote)
+	return err
+}
+
+// syncPush pushes every local task whose LocalID is new or whose UpdatedAt
+// has advanced since the last push, so repeated pushes don't keep re-mailing
+// unchanged tasks.
+func syncPush() error {
+	cfg, err := loadSyncConfig()
+	if err != nil {
+		return err
+	}
+	state, err := loadSyncState()
+	if err != nil {
+		return err
+	}
+
+	tasks, err := store.List(Filter{})
+	if err != nil {
+		return err
+	}
+
+	remote := cfg.remote()
+	pushed := 0
+	for _, task := range tasks {
+		if task.LocalID == "" {
+			id, err := newLocalID()
+			if err != nil {
+				return err
+			}
+			task.LocalID = id
+			if err := store.Update(task); err != nil {
+				return err
+			}
+		}
+
+		if last, ok := state[task.LocalID]; ok && !task.UpdatedAt.After(last) {
+			continue
+		}
+
+		body, err := json.Marshal(task)
+		if err != nil {
+			return fmt.Errorf("marshaling task %d: %v", task.ID, err)
+		}
+		if err := remote.Push(fmt.Sprintf("task:%s", task.LocalID), body); err != nil {
+			return fmt.Errorf("sending task %d: %v", task.ID, err)
+		}
+
+		state[task.LocalID] = task.UpdatedAt
+		pushed++
+	}
+
+	if err := saveSyncState(state); err != nil {
+		return err
+	}
+
+	fmt.Printf("Pushed %d task(s) to %s\n", pushed, cfg.Username)
+	return nil
+}
+
+// syncPull pulls tasks from the configured mail remote and merges them into
+// the local store.
+func syncPull() error {
+	cfg, err := loadSyncConfig()
+	if err != nil {
+		return err
+	}
+	state, err := loadSyncState()
+	if err != nil {
+		return err
+	}
+
+	remote := cfg.remote()
+	messages, err := remote.Pull()
+	if err != nil {
+		return err
+	}
+
+	for _, msg := range messages {
+		var task Task
+		if err := json.Unmarshal(msg.Body, &task); err != nil {
+			return fmt.Errorf("parsing message uid %s: %v", msg.UID, err)
+		}
+		task.RemoteID = msg.UID
+
+		if err := mergeRemoteTask(store, task); err != nil {
+			return err
+		}
+		state[task.LocalID] = task.UpdatedAt
+	}
+
+	if err := saveSyncState(state); err != nil {
+		return err
+	}
+
+	fmt.Printf("Pulled %d task(s) from %s\n", len(messages), cfg.Folder)
+	return nil
+}
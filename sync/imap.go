@@ -0,0 +1,203 @@
+package sync
+
+import (
+	"bufio"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net/textproto"
+	"strconv"
+	"strings"
+)
+
+// imapClient is a minimal IMAP4rev1 client supporting just the commands
+// `sync push` and `sync pull` need: LOGIN, APPEND, SELECT, UID SEARCH,
+// UID FETCH and deleting processed messages. It is not a general-purpose
+// IMAP implementation.
+type imapClient struct {
+	conn   io.ReadWriteCloser
+	r      *textproto.Reader
+	tagNum int
+}
+
+// dialIMAP opens a TLS connection to an IMAP server and consumes its
+// greeting.
+func dialIMAP(addr string) (*imapClient, error) {
+	conn, err := tls.Dial("tcp", addr, nil)
+	if err != nil {
+		return nil, fmt.Errorf("imap dial: %v", err)
+	}
+
+	c := &imapClient{conn: conn, r: textproto.NewReader(bufio.NewReader(conn))}
+	if _, err := c.r.ReadLine(); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("imap greeting: %v", err)
+	}
+	return c, nil
+}
+
+func (c *imapClient) nextTag() string {
+	c.tagNum++
+	return fmt.Sprintf("a%03d", c.tagNum)
+}
+
+// quoteIMAP wraps a string in IMAP quoted-string syntax.
+func quoteIMAP(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	return `"` + s + `"`
+}
+
+// simpleCmd sends a command and consumes responses up to the tagged
+// completion line, returning an error unless the server replied OK.
+func (c *imapClient) simpleCmd(format string, args ...interface{}) error {
+	tag := c.nextTag()
+	line := fmt.Sprintf("%s %s\r\n", tag, fmt.Sprintf(format, args...))
+	if _, err := io.WriteString(c.conn, line); err != nil {
+		return err
+	}
+
+	for {
+		resp, err := c.r.ReadLine()
+		if err != nil {
+			return err
+		}
+		if strings.HasPrefix(resp, tag+" ") {
+			if !strings.Contains(resp, "OK") {
+				return fmt.Errorf("imap command %q failed: %s", format, resp)
+			}
+			return nil
+		}
+	}
+}
+
+func (c *imapClient) login(user, pass string) error {
+	return c.simpleCmd("LOGIN %s %s", quoteIMAP(user), quoteIMAP(pass))
+}
+
+func (c *imapClient) selectFolder(folder string) error {
+	return c.simpleCmd("SELECT %s", quoteIMAP(folder))
+}
+
+// append uploads message into folder via IMAP APPEND. Unlike SELECT,
+// APPEND doesn't require the mailbox to be selected first, only that the
+// connection is authenticated.
+func (c *imapClient) append(folder string, message []byte) error {
+	tag := c.nextTag()
+	if _, err := io.WriteString(c.conn, fmt.Sprintf("%s APPEND %s {%d}\r\n", tag, quoteIMAP(folder), len(message))); err != nil {
+		return err
+	}
+
+	cont, err := c.r.ReadLine()
+	if err != nil {
+		return err
+	}
+	if !strings.HasPrefix(cont, "+") {
+		return fmt.Errorf("imap APPEND not continued: %s", cont)
+	}
+
+	if _, err := c.conn.Write(message); err != nil {
+		return err
+	}
+	if _, err := io.WriteString(c.conn, "\r\n"); err != nil {
+		return err
+	}
+
+	for {
+		resp, err := c.r.ReadLine()
+		if err != nil {
+			return err
+		}
+		if strings.HasPrefix(resp, tag+" ") {
+			if !strings.Contains(resp, "OK") {
+				return fmt.Errorf("imap APPEND failed: %s", resp)
+			}
+			return nil
+		}
+	}
+}
+
+func (c *imapClient) logout() {
+	io.WriteString(c.conn, fmt.Sprintf("%s LOGOUT\r\n", c.nextTag()))
+	c.conn.Close()
+}
+
+// searchAllUIDs returns the UIDs of every message in the selected folder.
+func (c *imapClient) searchAllUIDs() ([]string, error) {
+	tag := c.nextTag()
+	if _, err := io.WriteString(c.conn, fmt.Sprintf("%s UID SEARCH ALL\r\n", tag)); err != nil {
+		return nil, err
+	}
+
+	var uids []string
+	for {
+		line, err := c.r.ReadLine()
+		if err != nil {
+			return nil, err
+		}
+		if strings.HasPrefix(line, "* SEARCH") {
+			uids = strings.Fields(strings.TrimPrefix(line, "* SEARCH"))
+		}
+		if strings.HasPrefix(line, tag+" ") {
+			if !strings.Contains(line, "OK") {
+				return nil, fmt.Errorf("imap UID SEARCH failed: %s", line)
+			}
+			return uids, nil
+		}
+	}
+}
+
+// fetchMessage downloads the raw RFC-822 content of a message by UID.
+func (c *imapClient) fetchMessage(uid string) ([]byte, error) {
+	tag := c.nextTag()
+	if _, err := io.WriteString(c.conn, fmt.Sprintf("%s UID FETCH %s (RFC822)\r\n", tag, uid)); err != nil {
+		return nil, err
+	}
+
+	for {
+		line, err := c.r.ReadLine()
+		if err != nil {
+			return nil, err
+		}
+		if strings.HasPrefix(line, tag+" ") {
+			return nil, fmt.Errorf("imap UID FETCH %s returned no message: %s", uid, line)
+		}
+
+		idx := strings.LastIndexByte(line, '{')
+		if idx == -1 || !strings.HasSuffix(line, "}") {
+			continue
+		}
+		n, err := strconv.Atoi(line[idx+1 : len(line)-1])
+		if err != nil {
+			continue
+		}
+
+		buf := make([]byte, n)
+		if _, err := io.ReadFull(c.r.R, buf); err != nil {
+			return nil, err
+		}
+
+		// Drain the remainder of the FETCH response up to the tagged
+		// completion line (closing parens etc. carry no data we need).
+		for {
+			rest, err := c.r.ReadLine()
+			if err != nil {
+				return nil, err
+			}
+			if strings.HasPrefix(rest, tag+" ") {
+				if !strings.Contains(rest, "OK") {
+					return nil, fmt.Errorf("imap UID FETCH %s failed: %s", uid, rest)
+				}
+				return buf, nil
+			}
+		}
+	}
+}
+
+// deleteMessage marks a message \Deleted and expunges it immediately.
+func (c *imapClient) deleteMessage(uid string) error {
+	if err := c.simpleCmd(`UID STORE %s +FLAGS (\Deleted)`, uid); err != nil {
+		return err
+	}
+	return c.simpleCmd("EXPUNGE")
+}
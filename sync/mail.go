@@ -0,0 +1,118 @@
+// Package sync implements a minimal IMAP transport for exchanging opaque
+// message bodies through a shared mail inbox. It knows nothing about tasks
+// - the caller hands Push a body to send and gets raw Messages back from
+// Pull - so it can be reused by any future sync backend that wants mail as
+// its transport.
+package sync
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Config holds the IMAP settings needed to reach the shared inbox.
+type Config struct {
+	IMAPHost string
+	IMAPPort string
+	Username string
+	Password string
+	Folder   string
+}
+
+// Message is one item pulled from the inbox: its IMAP UID (stable for as
+// long as the message exists, so callers can use it to recognize
+// already-seen messages) and its RFC-822 body, header section stripped.
+type Message struct {
+	UID  string
+	Body []byte
+}
+
+// Remote pushes and pulls opaque message bodies through a mail account:
+// Push uploads body as an RFC-822 message tagged with subject, Pull fetches
+// and deletes every message currently in Folder.
+type Remote struct {
+	cfg Config
+}
+
+// NewRemote returns a Remote configured to talk to cfg's mail account.
+func NewRemote(cfg Config) *Remote {
+	return &Remote{cfg: cfg}
+}
+
+// Push uploads body as a new message into the configured IMAP folder via
+// APPEND, tagged with subject. Sending through SMTP instead isn't reliable
+// here: a self-addressed message lands wherever the mail server's default
+// delivery puts it (usually INBOX), not necessarily Folder, so a later Pull
+// searching Folder would find nothing. APPEND writes directly into the
+// mailbox Pull reads from.
+func (r *Remote) Push(subject string, body []byte) error {
+	client, err := dialIMAP(r.cfg.IMAPHost + ":" + r.cfg.IMAPPort)
+	if err != nil {
+		return err
+	}
+	defer client.logout()
+
+	if err := client.login(r.cfg.Username, r.cfg.Password); err != nil {
+		return err
+	}
+
+	msg := fmt.Sprintf(
+		"From: %s\r\nTo: %s\r\nSubject: %s\r\n\r\n%s\r\n",
+		r.cfg.Username, r.cfg.Username, subject, body,
+	)
+
+	if err := client.append(r.cfg.Folder, []byte(msg)); err != nil {
+		return fmt.Errorf("appending message %q: %v", subject, err)
+	}
+	return nil
+}
+
+// Pull fetches every message currently in the configured IMAP folder and
+// deletes each one server-side once read.
+func (r *Remote) Pull() ([]Message, error) {
+	client, err := dialIMAP(r.cfg.IMAPHost + ":" + r.cfg.IMAPPort)
+	if err != nil {
+		return nil, err
+	}
+	defer client.logout()
+
+	if err := client.login(r.cfg.Username, r.cfg.Password); err != nil {
+		return nil, err
+	}
+	if err := client.selectFolder(r.cfg.Folder); err != nil {
+		return nil, err
+	}
+
+	uids, err := client.searchAllUIDs()
+	if err != nil {
+		return nil, err
+	}
+
+	var messages []Message
+	for _, uid := range uids {
+		raw, err := client.fetchMessage(uid)
+		if err != nil {
+			return nil, err
+		}
+
+		body, err := messageBody(raw)
+		if err != nil {
+			return nil, fmt.Errorf("parsing message uid %s: %v", uid, err)
+		}
+		messages = append(messages, Message{UID: uid, Body: body})
+
+		if err := client.deleteMessage(uid); err != nil {
+			return nil, fmt.Errorf("deleting message uid %s: %v", uid, err)
+		}
+	}
+	return messages, nil
+}
+
+// messageBody strips the RFC-822 header section, returning the body.
+func messageBody(raw []byte) ([]byte, error) {
+	parts := strings.SplitN(string(raw), "\r\n\r\n", 2)
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("message has no body")
+	}
+	return []byte(strings.TrimSpace(parts[1])), nil
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMergeRemoteTaskNewLocalIDIsAdded(t *testing.T) {
+	withTempStore(t)
+
+	remote := Task{ID: 1, LocalID: "abc123", Description: "from another device",
+		Status: StatusTodo, CreatedAt: time.Now(), UpdatedAt: time.Now()}
+
+	if err := mergeRemoteTask(store, remote); err != nil {
+		t.Fatalf("mergeRemoteTask: %v", err)
+	}
+
+	tasks, err := store.List(Filter{})
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(tasks) != 1 || tasks[0].LocalID != "abc123" {
+		t.Fatalf("store = %+v, want a single task with LocalID abc123", tasks)
+	}
+}
+
+func TestMergeRemoteTaskNewerUpdateWins(t *testing.T) {
+	withTempStore(t)
+
+	older := time.Now().Add(-time.Hour)
+	newer := time.Now()
+
+	created, err := store.Add(Task{LocalID: "xyz", Description: "stale", Status: StatusTodo, CreatedAt: older, UpdatedAt: older})
+	if err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+
+	remote := Task{LocalID: "xyz", Description: "fresh", Status: StatusTodo, CreatedAt: older, UpdatedAt: newer}
+	if err := mergeRemoteTask(store, remote); err != nil {
+		t.Fatalf("mergeRemoteTask: %v", err)
+	}
+
+	found, err := store.Find(created.ID)
+	if err != nil {
+		t.Fatalf("Find: %v", err)
+	}
+	if found.Description != "fresh" {
+		t.Errorf("after merging a newer remote update, Find() = %+v, want Description=fresh", found)
+	}
+}
+
+func TestMergeRemoteTaskStaleUpdateIsIgnored(t *testing.T) {
+	withTempStore(t)
+
+	older := time.Now().Add(-time.Hour)
+	newer := time.Now()
+
+	created, err := store.Add(Task{LocalID: "xyz", Description: "current", Status: StatusTodo, CreatedAt: older, UpdatedAt: newer})
+	if err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+
+	remote := Task{LocalID: "xyz", Description: "outdated", Status: StatusTodo, CreatedAt: older, UpdatedAt: older}
+	if err := mergeRemoteTask(store, remote); err != nil {
+		t.Fatalf("mergeRemoteTask: %v", err)
+	}
+
+	found, err := store.Find(created.ID)
+	if err != nil {
+		t.Fatalf("Find: %v", err)
+	}
+	if found.Description != "current" {
+		t.Errorf("a stale remote update should be ignored, got Description=%q", found.Description)
+	}
+}
+
+func TestSyncStateRoundTrip(t *testing.T) {
+	withTempStore(t)
+
+	state := syncState{"abc": time.Date(2026, time.July, 1, 0, 0, 0, 0, time.UTC)}
+	if err := saveSyncState(state); err != nil {
+		t.Fatalf("saveSyncState: %v", err)
+	}
+
+	loaded, err := loadSyncState()
+	if err != nil {
+		t.Fatalf("loadSyncState: %v", err)
+	}
+	if !loaded["abc"].Equal(state["abc"]) {
+		t.Errorf("loadSyncState() = %v, want %v", loaded, state)
+	}
+}
+
+func TestLoadSyncStateMissingFileIsEmpty(t *testing.T) {
+	withTempStore(t)
+
+	state, err := loadSyncState()
+	if err != nil {
+		t.Fatalf("loadSyncState: %v", err)
+	}
+	if len(state) != 0 {
+		t.Errorf("loadSyncState() on a missing file = %v, want empty", state)
+	}
+}